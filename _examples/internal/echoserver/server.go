@@ -12,7 +12,6 @@ import (
 	"github.com/bartventer/gorm-multitenancy/examples/v8/internal/models"
 	echomw "github.com/bartventer/gorm-multitenancy/middleware/echo/v8"
 	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
-	"github.com/bartventer/gorm-multitenancy/v8/pkg/scopes"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -25,10 +24,15 @@ type controller struct {
 func (c *controller) init(e *echo.Echo) {
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
+	skipTenantRoutes := func(c echo.Context) bool {
+		return strings.HasPrefix(c.Request().URL.Path, "/tenants") // skip tenant routes
+	}
 	e.Use(echomw.WithTenant(echomw.WithTenantConfig{
-		Skipper: func(c echo.Context) bool {
-			return strings.HasPrefix(c.Request().URL.Path, "/tenants") // skip tenant routes
-		},
+		Skipper: skipTenantRoutes,
+	}))
+	e.Use(echomw.WithTransaction(c.db, echomw.WithTransactionConfig{
+		Skipper:  skipTenantRoutes,
+		ReadOnly: echomw.DefaultReadOnly,
 	}))
 
 	e.POST("/tenants", c.createTenantHandler)
@@ -89,6 +93,14 @@ func TenantFromContext(c echo.Context) (string, error) {
 	return tenantID, nil
 }
 
+func TenantDBFromContext(c echo.Context) (*multitenancy.DB, error) {
+	tx, ok := echomw.DBFromContext(c)
+	if !ok {
+		return nil, errors.New("no tenant database in context")
+	}
+	return tx, nil
+}
+
 func (cr *controller) createTenantHandler(c echo.Context) error {
 	var body models.CreateTenantBody
 	var err error
@@ -105,10 +117,7 @@ func (cr *controller) createTenantHandler(c echo.Context) error {
 			SchemaName: subdomain,
 		},
 	}
-	if err = cr.db.Create(tenant).Error; err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-	}
-	if err = cr.db.MigrateTenantModels(context.Background(), tenant.SchemaName); err != nil {
+	if err = cr.db.ProvisionTenant(context.Background(), tenant); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
@@ -145,12 +154,12 @@ func (cr *controller) deleteTenantHandler(c echo.Context) error {
 }
 
 func (cr *controller) getBooksHandler(c echo.Context) error {
-	tenantID, err := TenantFromContext(c)
+	tx, err := TenantDBFromContext(c)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 	var books []models.BookResponse
-	if err = cr.db.Table(models.TableNameBook).Scopes(scopes.WithTenantSchema(tenantID)).Find(&books).Error; err != nil {
+	if err = tx.Table(models.TableNameBook).Find(&books).Error; err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 	return c.JSON(http.StatusOK, books)
@@ -161,17 +170,16 @@ func (cr *controller) createBookHandler(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	tx, err := TenantDBFromContext(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
 	var book models.Book
 	if err = c.Bind(&book); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 	book.TenantSchema = tenantID
-	reset, tenantErr := cr.db.UseTenant(context.Background(), tenantID)
-	if tenantErr != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, tenantErr.Error())
-	}
-	defer reset()
-	if err = cr.db.Create(&book).Error; err != nil {
+	if err = tx.Create(&book).Error; err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
@@ -183,23 +191,23 @@ func (cr *controller) createBookHandler(c echo.Context) error {
 }
 
 func (cr *controller) deleteBookHandler(c echo.Context) error {
-	tenantID, err := TenantFromContext(c)
+	tx, err := TenantDBFromContext(c)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 	bookID := c.Param("id")
 	var book models.Book
-	if err = cr.db.Scopes(scopes.WithTenantSchema(tenantID)).First(&book, bookID).Error; err != nil {
+	if err = tx.First(&book, bookID).Error; err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
-	if err = cr.db.Scopes(scopes.WithTenantSchema(tenantID)).Delete(&models.Book{}, bookID).Error; err != nil {
+	if err = tx.Delete(&models.Book{}, bookID).Error; err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
 func (cr *controller) updateBookHandler(c echo.Context) error {
-	tenantID, err := TenantFromContext(c)
+	tx, err := TenantDBFromContext(c)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -212,12 +220,7 @@ func (cr *controller) updateBookHandler(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
 	}
 	book := &models.Book{}
-	reset, tenantErr := cr.db.UseTenant(context.Background(), tenantID)
-	if tenantErr != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, tenantErr.Error())
-	}
-	defer reset()
-	if err = cr.db.Model(book).Where("id = ?", bookID).Updates(models.Book{
+	if err = tx.Model(book).Where("id = ?", bookID).Updates(models.Book{
 		Name: body.Name,
 	}).Error; err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
@@ -12,7 +12,6 @@ import (
 	"github.com/bartventer/gorm-multitenancy/examples/v8/internal/models"
 	ginmw "github.com/bartventer/gorm-multitenancy/middleware/gin/v8"
 	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
-	"github.com/bartventer/gorm-multitenancy/v8/pkg/scopes"
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,10 +26,15 @@ func Start(ctx context.Context, db *multitenancy.DB) error {
 }
 
 func (c *controller) init(r *gin.Engine) {
+	skipTenantRoutes := func(c *gin.Context) bool {
+		return strings.HasPrefix(c.Request.URL.Path, "/tenants") // skip tenant routes
+	}
 	r.Use(ginmw.WithTenant(ginmw.WithTenantConfig{
-		Skipper: func(c *gin.Context) bool {
-			return strings.HasPrefix(c.Request.URL.Path, "/tenants") // skip tenant routes
-		},
+		Skipper: skipTenantRoutes,
+	}))
+	r.Use(ginmw.WithTransaction(c.db, ginmw.WithTransactionConfig{
+		Skipper:  skipTenantRoutes,
+		ReadOnly: ginmw.DefaultReadOnly,
 	}))
 
 	r.POST("/tenants", c.createTenantHandler)
@@ -86,6 +90,14 @@ func TenantFromContext(c *gin.Context) (string, error) {
 	return tenantID.(string), nil
 }
 
+func TenantDBFromContext(c *gin.Context) (*multitenancy.DB, error) {
+	tx, ok := ginmw.DBFromContext(c)
+	if !ok {
+		return nil, errors.New("no tenant database in context")
+	}
+	return tx, nil
+}
+
 func (cr *controller) createTenantHandler(c *gin.Context) {
 	var body models.CreateTenantBody
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -103,11 +115,7 @@ func (cr *controller) createTenantHandler(c *gin.Context) {
 			SchemaName: subdomain,
 		},
 	}
-	if err := cr.db.Create(tenant).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if err := cr.db.MigrateTenantModels(context.Background(), tenant.SchemaName); err != nil {
+	if err := cr.db.ProvisionTenant(context.Background(), tenant); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -148,13 +156,13 @@ func (cr *controller) deleteTenantHandler(c *gin.Context) {
 }
 
 func (cr *controller) getBooksHandler(c *gin.Context) {
-	tenantID, err := TenantFromContext(c)
+	tx, err := TenantDBFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	var books []models.BookResponse
-	if err := cr.db.Table(models.TableNameBook).Scopes(scopes.WithTenantSchema(tenantID)).Find(&books).Error; err != nil {
+	if err := tx.Table(models.TableNameBook).Find(&books).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -167,19 +175,18 @@ func (cr *controller) createBookHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	tx, err := TenantDBFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	var book models.Book
 	if err := c.ShouldBindJSON(&book); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	book.TenantSchema = tenantID
-	reset, tenantErr := cr.db.UseTenant(context.Background(), tenantID)
-	if tenantErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": tenantErr.Error()})
-		return
-	}
-	defer reset()
-	if err := cr.db.Create(&book).Error; err != nil {
+	if err := tx.Create(&book).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -192,18 +199,18 @@ func (cr *controller) createBookHandler(c *gin.Context) {
 }
 
 func (cr *controller) deleteBookHandler(c *gin.Context) {
-	tenantID, err := TenantFromContext(c)
+	tx, err := TenantDBFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	bookID := c.Param("id")
 	var book models.Book
-	if err := cr.db.Scopes(scopes.WithTenantSchema(tenantID)).First(&book, bookID).Error; err != nil {
+	if err := tx.First(&book, bookID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
-	if err := cr.db.Scopes(scopes.WithTenantSchema(tenantID)).Delete(&models.Book{}, bookID).Error; err != nil {
+	if err := tx.Delete(&models.Book{}, bookID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -211,7 +218,7 @@ func (cr *controller) deleteBookHandler(c *gin.Context) {
 }
 
 func (cr *controller) updateBookHandler(c *gin.Context) {
-	tenantID, err := TenantFromContext(c)
+	tx, err := TenantDBFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -227,13 +234,7 @@ func (cr *controller) updateBookHandler(c *gin.Context) {
 		return
 	}
 	book := &models.Book{}
-	reset, tenantErr := cr.db.UseTenant(context.Background(), tenantID)
-	if tenantErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": tenantErr.Error()})
-		return
-	}
-	defer reset()
-	if err := cr.db.Model(book).Where("id = ?", bookID).Updates(models.Book{Name: body.Name}).Error; err != nil {
+	if err := tx.Model(book).Where("id = ?", bookID).Updates(models.Book{Name: body.Name}).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
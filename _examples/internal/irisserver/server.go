@@ -12,7 +12,6 @@ import (
 	"github.com/bartventer/gorm-multitenancy/examples/v8/internal/models"
 	irismiddleware "github.com/bartventer/gorm-multitenancy/middleware/iris/v8"
 	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
-	"github.com/bartventer/gorm-multitenancy/v8/pkg/scopes"
 	"github.com/kataras/iris/v12"
 )
 
@@ -27,10 +26,15 @@ func Start(ctx context.Context, db *multitenancy.DB) error {
 }
 
 func (c *controller) init(app *iris.Application) {
+	skipTenantRoutes := func(ctx iris.Context) bool {
+		return strings.HasPrefix(ctx.Request().URL.Path, "/tenants") // skip tenant routes
+	}
 	app.Use(irismiddleware.WithTenant(irismiddleware.WithTenantConfig{
-		Skipper: func(ctx iris.Context) bool {
-			return strings.HasPrefix(ctx.Request().URL.Path, "/tenants") // skip tenant routes
-		},
+		Skipper: skipTenantRoutes,
+	}))
+	app.Use(irismiddleware.WithTransaction(c.db, irismiddleware.WithTransactionConfig{
+		Skipper:  skipTenantRoutes,
+		ReadOnly: irismiddleware.DefaultReadOnly,
 	}))
 
 	app.Post("/tenants", c.createTenantHandler)
@@ -86,6 +90,14 @@ func TenantFromContext(ctx iris.Context) (string, error) {
 	return tenantID, nil
 }
 
+func TenantDBFromContext(ctx iris.Context) (*multitenancy.DB, error) {
+	tx, ok := irismiddleware.DBFromContext(ctx)
+	if !ok {
+		return nil, errors.New("no tenant database in context")
+	}
+	return tx, nil
+}
+
 func (cr *controller) createTenantHandler(ctx iris.Context) {
 	var body models.CreateTenantBody
 	if err := ctx.ReadJSON(&body); err != nil {
@@ -105,12 +117,7 @@ func (cr *controller) createTenantHandler(ctx iris.Context) {
 			SchemaName: subdomain,
 		},
 	}
-	if err := cr.db.Create(tenant).Error; err != nil {
-		ctx.StatusCode(http.StatusInternalServerError)
-		ctx.JSON(iris.Map{"error": err.Error()})
-		return
-	}
-	if err := cr.db.MigrateTenantModels(context.Background(), tenant.SchemaName); err != nil {
+	if err := cr.db.ProvisionTenant(context.Background(), tenant); err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": err.Error()})
 		return
@@ -157,14 +164,14 @@ func (cr *controller) deleteTenantHandler(ctx iris.Context) {
 }
 
 func (cr *controller) getBooksHandler(ctx iris.Context) {
-	tenantID, err := TenantFromContext(ctx)
+	tx, err := TenantDBFromContext(ctx)
 	if err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": err.Error()})
 		return
 	}
 	var books []models.BookResponse
-	if err := cr.db.Table(models.TableNameBook).Scopes(scopes.WithTenantSchema(tenantID)).Find(&books).Error; err != nil {
+	if err := tx.Table(models.TableNameBook).Find(&books).Error; err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": err.Error()})
 		return
@@ -179,6 +186,12 @@ func (cr *controller) createBookHandler(ctx iris.Context) {
 		ctx.JSON(iris.Map{"error": err.Error()})
 		return
 	}
+	tx, err := TenantDBFromContext(ctx)
+	if err != nil {
+		ctx.StatusCode(http.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": err.Error()})
+		return
+	}
 	var book models.Book
 	if err := ctx.ReadJSON(&book); err != nil {
 		ctx.StatusCode(http.StatusBadRequest)
@@ -186,14 +199,7 @@ func (cr *controller) createBookHandler(ctx iris.Context) {
 		return
 	}
 	book.TenantSchema = tenantID
-	reset, tenantErr := cr.db.UseTenant(context.Background(), tenantID)
-	if tenantErr != nil {
-		ctx.StatusCode(http.StatusInternalServerError)
-		ctx.JSON(iris.Map{"error": tenantErr.Error()})
-		return
-	}
-	defer reset()
-	if err := cr.db.Create(&book).Error; err != nil {
+	if err := tx.Create(&book).Error; err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": err.Error()})
 		return
@@ -208,7 +214,7 @@ func (cr *controller) createBookHandler(ctx iris.Context) {
 }
 
 func (cr *controller) deleteBookHandler(ctx iris.Context) {
-	tenantID, err := TenantFromContext(ctx)
+	tx, err := TenantDBFromContext(ctx)
 	if err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": err.Error()})
@@ -216,12 +222,12 @@ func (cr *controller) deleteBookHandler(ctx iris.Context) {
 	}
 	bookID := ctx.Params().Get("id")
 	var book models.Book
-	if err := cr.db.Scopes(scopes.WithTenantSchema(tenantID)).First(&book, bookID).Error; err != nil {
+	if err := tx.First(&book, bookID).Error; err != nil {
 		ctx.StatusCode(http.StatusNotFound)
 		ctx.JSON(iris.Map{"error": err.Error()})
 		return
 	}
-	if err := cr.db.Scopes(scopes.WithTenantSchema(tenantID)).Delete(&models.Book{}, bookID).Error; err != nil {
+	if err := tx.Delete(&models.Book{}, bookID).Error; err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": err.Error()})
 		return
@@ -230,7 +236,7 @@ func (cr *controller) deleteBookHandler(ctx iris.Context) {
 }
 
 func (cr *controller) updateBookHandler(ctx iris.Context) {
-	tenantID, err := TenantFromContext(ctx)
+	tx, err := TenantDBFromContext(ctx)
 	if err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": err.Error()})
@@ -249,14 +255,7 @@ func (cr *controller) updateBookHandler(ctx iris.Context) {
 		return
 	}
 	book := &models.Book{}
-	reset, tenantErr := cr.db.UseTenant(context.Background(), tenantID)
-	if tenantErr != nil {
-		ctx.StatusCode(http.StatusInternalServerError)
-		ctx.JSON(iris.Map{"error": tenantErr.Error()})
-		return
-	}
-	defer reset()
-	if err := cr.db.Model(book).Where("id = ?", bookID).Updates(models.Book{Name: body.Name}).Error; err != nil {
+	if err := tx.Model(book).Where("id = ?", bookID).Updates(models.Book{Name: body.Name}).Error; err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": err.Error()})
 		return
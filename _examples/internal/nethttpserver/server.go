@@ -14,7 +14,6 @@ import (
 	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
 
 	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
-	"github.com/bartventer/gorm-multitenancy/v8/pkg/scopes"
 	"github.com/urfave/negroni"
 )
 
@@ -37,11 +36,15 @@ func (c *controller) init(mux *http.ServeMux, n *negroni.Negroni) {
 	mux.HandleFunc("DELETE /books/{id}", c.deleteBookHandler)
 	mux.HandleFunc("PUT /books/{id}", c.updateBookHandler)
 
+	skipTenantRoutes := func(r *http.Request) bool {
+		return strings.HasPrefix(r.URL.Path, "/tenants")
+	}
 	n.UseHandler(nethttpmw.WithTenant(nethttpmw.WithTenantConfig{
-		Skipper: func(r *http.Request) bool {
-			return strings.HasPrefix(r.URL.Path, "/tenants")
-		},
-	})(mux))
+		Skipper: skipTenantRoutes,
+	})(nethttpmw.WithTransaction(c.db, nethttpmw.WithTransactionConfig{
+		Skipper:  skipTenantRoutes,
+		ReadOnly: nethttpmw.DefaultReadOnly,
+	})(mux)))
 }
 
 func (cr *controller) start(ctx context.Context) (err error) {
@@ -90,6 +93,14 @@ func TenantFromContext(ctx context.Context) (string, error) {
 	return tenant, nil
 }
 
+func TenantDBFromContext(ctx context.Context) (*multitenancy.DB, error) {
+	tx, ok := nethttpmw.DBFromContext(ctx)
+	if !ok {
+		return nil, errors.New("no tenant database in context")
+	}
+	return tx, nil
+}
+
 func (cr *controller) createTenantHandler(w http.ResponseWriter, r *http.Request) {
 	var body models.CreateTenantBody
 	var err error
@@ -108,11 +119,7 @@ func (cr *controller) createTenantHandler(w http.ResponseWriter, r *http.Request
 			SchemaName: subdomain,
 		},
 	}
-	if err = cr.db.Create(tenant).Error; err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if err = cr.db.MigrateTenantModels(context.Background(), tenant.SchemaName); err != nil {
+	if err = cr.db.ProvisionTenant(context.Background(), tenant); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -162,13 +169,13 @@ func (cr *controller) deleteTenantHandler(w http.ResponseWriter, r *http.Request
 }
 
 func (cr *controller) getBooksHandler(w http.ResponseWriter, r *http.Request) {
-	tenantID, err := TenantFromContext(r.Context())
+	tx, err := TenantDBFromContext(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	var books []models.BookResponse
-	if err = cr.db.Table(models.TableNameBook).Scopes(scopes.WithTenantSchema(tenantID)).Find(&books).Error; err != nil {
+	if err = tx.Table(models.TableNameBook).Find(&books).Error; err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -184,6 +191,11 @@ func (cr *controller) createBookHandler(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	tx, err := TenantDBFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	var book models.Book
 	if err = json.NewDecoder(r.Body).Decode(&book); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -191,13 +203,7 @@ func (cr *controller) createBookHandler(w http.ResponseWriter, r *http.Request)
 	}
 	book.TenantSchema = tenantID
 
-	reset, tenantErr := cr.db.UseTenant(context.Background(), tenantID)
-	if tenantErr != nil {
-		http.Error(w, tenantErr.Error(), http.StatusInternalServerError)
-		return
-	}
-	defer reset()
-	if err = cr.db.Create(&book).Error; err != nil {
+	if err = tx.Create(&book).Error; err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -213,7 +219,7 @@ func (cr *controller) createBookHandler(w http.ResponseWriter, r *http.Request)
 }
 
 func (cr *controller) deleteBookHandler(w http.ResponseWriter, r *http.Request) {
-	tenantID, err := TenantFromContext(r.Context())
+	tx, err := TenantDBFromContext(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -221,11 +227,11 @@ func (cr *controller) deleteBookHandler(w http.ResponseWriter, r *http.Request)
 	bookID := r.PathValue("id")
 
 	var book models.Book
-	if err = cr.db.Scopes(scopes.WithTenantSchema(tenantID)).First(&book, bookID).Error; err != nil {
+	if err = tx.First(&book, bookID).Error; err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
-	if err = cr.db.Scopes(scopes.WithTenantSchema(tenantID)).Delete(&models.Book{}, bookID).Error; err != nil {
+	if err = tx.Delete(&models.Book{}, bookID).Error; err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -233,7 +239,7 @@ func (cr *controller) deleteBookHandler(w http.ResponseWriter, r *http.Request)
 }
 
 func (cr *controller) updateBookHandler(w http.ResponseWriter, r *http.Request) {
-	tenantID, err := TenantFromContext(r.Context())
+	tx, err := TenantDBFromContext(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -250,13 +256,7 @@ func (cr *controller) updateBookHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	var book models.Book
-	reset, tenantErr := cr.db.UseTenant(context.Background(), tenantID)
-	if tenantErr != nil {
-		http.Error(w, tenantErr.Error(), http.StatusInternalServerError)
-		return
-	}
-	defer reset()
-	if err = cr.db.Model(&book).Where("id = ?", bookID).Updates(models.Book{
+	if err = tx.Model(&book).Where("id = ?", bookID).Updates(models.Book{
 		Name: body.Name,
 	}).Error; err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -48,6 +48,9 @@ var _ driver.TenantTabler = new(Book)
 func (Tenant) TableName() string   { return "public.tenants" }
 func (Tenant) IsSharedModel() bool { return true }
 
+// TenantSchemaName implements [multitenancy.TenantIdentifier].
+func (t Tenant) TenantSchemaName() string { return t.ID }
+
 func (Author) TableName() string   { return "authors" }
 func (Author) IsSharedModel() bool { return false }
 
@@ -57,10 +60,41 @@ func (Language) IsSharedModel() bool { return false }
 func (Book) TableName() string   { return "books" }
 func (Book) IsSharedModel() bool { return false }
 
+// Review is a tenant-specific model in the "premium" model group.
+type Review struct {
+	gorm.Model
+	BookID uint `gorm:"index"`
+	Body   string
+}
+
+var _ driver.TenantTabler = new(Review)
+var _ driver.ModelGrouper = new(Review)
+
+func (Review) TableName() string   { return "reviews" }
+func (Review) IsSharedModel() bool { return false }
+func (Review) ModelGroup() string  { return "premium" }
+
+// Models that declare their tenancy rather than implementing [driver.TenantTabler].
+type (
+	// Plan is a shared model, in table "public.plans".
+	Plan struct {
+		driver.SharedTable
+		gorm.Model
+		Name string
+	}
+
+	// Note is a tenant-specific model, in table "notes".
+	Note struct {
+		_ struct{} `gmt:"tenant"`
+		gorm.Model
+		Body string
+	}
+)
+
 // MakeAllModels returns all valid models for testing.
-func MakeAllModels[TB testing.TB](t TB) []driver.TenantTabler {
+func MakeAllModels[TB testing.TB](t TB) []interface{} {
 	t.Helper()
-	return []driver.TenantTabler{
+	return []interface{}{
 		// Shared
 		&Tenant{},
 		// Private
@@ -71,12 +105,12 @@ func MakeAllModels[TB testing.TB](t TB) []driver.TenantTabler {
 }
 
 // MakeSharedModels returns all valid shared models for testing.
-func MakeSharedModels[TB testing.TB](t TB) []driver.TenantTabler {
+func MakeSharedModels[TB testing.TB](t TB) []interface{} {
 	t.Helper()
 	all := MakeAllModels(t)
-	out := make([]driver.TenantTabler, 0, len(all))
+	out := make([]interface{}, 0, len(all))
 	for _, m := range all {
-		if m.IsSharedModel() {
+		if m.(driver.TenantTabler).IsSharedModel() {
 			out = append(out, m)
 		}
 	}
@@ -84,12 +118,12 @@ func MakeSharedModels[TB testing.TB](t TB) []driver.TenantTabler {
 }
 
 // MakePrivateModels returns all valid tenant-specific models for testing.
-func MakePrivateModels[TB testing.TB](t TB) []driver.TenantTabler {
+func MakePrivateModels[TB testing.TB](t TB) []interface{} {
 	t.Helper()
 	all := MakeAllModels(t)
-	out := make([]driver.TenantTabler, 0, len(all))
+	out := make([]interface{}, 0, len(all))
 	for _, m := range all {
-		if !m.IsSharedModel() {
+		if !m.(driver.TenantTabler).IsSharedModel() {
 			out = append(out, m)
 		}
 	}
@@ -100,11 +134,19 @@ func MakePrivateModels[TB testing.TB](t TB) []driver.TenantTabler {
 type (
 	TenantInvalid struct{} // invalid shared model.
 	BookInvalid   struct{} // invalid tenant-specific model.
+
+	// PublisherInvalid is a shared model that references a tenant-specific model.
+	PublisherInvalid struct {
+		gorm.Model
+		FeaturedBookID uint
+		FeaturedBook   Book
+	}
 )
 
 // Invalid models for testing.
 var _ driver.TenantTabler = new(TenantInvalid)
 var _ driver.TenantTabler = new(BookInvalid)
+var _ driver.TenantTabler = new(PublisherInvalid)
 
 func (TenantInvalid) TableName() string   { return "tenants" } // missing .public prefix
 func (TenantInvalid) IsSharedModel() bool { return true }
@@ -112,6 +154,9 @@ func (TenantInvalid) IsSharedModel() bool { return true }
 func (BookInvalid) TableName() string   { return "public.books" } // contains .public prefix
 func (BookInvalid) IsSharedModel() bool { return false }
 
+func (PublisherInvalid) TableName() string   { return "public.publishers" }
+func (PublisherInvalid) IsSharedModel() bool { return true }
+
 // FakeTenant embeds [gorm.Model] and [multitenancy.TenantModel].
 type FakeTenant struct {
 	gorm.Model
@@ -0,0 +1,116 @@
+package multitenancy
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/backoff"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
+	"gorm.io/gorm"
+)
+
+// TenantState returns the lifecycle state of the tenant, or an empty state if the lifecycle of the
+// tenant is not tracked yet. See [DB.TransitionTenant] for details.
+func (db *DB) TenantState(ctx context.Context, tenantID string) (migrator.TenantState, error) {
+	state, err := migrator.LoadTenantState(db.DB.WithContext(ctx), tenantID)
+	if err != nil {
+		return "", gmterrors.New(fmt.Errorf("failed to load state of tenant %s: %w", tenantID, err))
+	}
+	return state, nil
+}
+
+// TransitionTenant transitions the tenant to the given lifecycle state, persisting the change in
+// the public schema. It returns an error wrapping [migrator.ErrInvalidTransition] if the tenant
+// cannot transition from its current state to the given state, such as migrating a tenant that
+// is being offboarded (see [migrator.TenantState.CanTransition]).
+//
+// The lifecycle of a tenant is tracked from its first transition. [DB.ProvisionTenant],
+// [DB.MigrateTenantModels] and [DB.OffboardTenant] update the state of the tenant as they run,
+// so that background jobs can coordinate with onboarding and offboarding. Use this method for
+// the remaining transitions, such as suspending a tenant.
+//
+// Safe for concurrent use by multiple goroutines.
+func (db *DB) TransitionTenant(ctx context.Context, tenantID string, to migrator.TenantState) error {
+	if _, err := migrator.TransitionTenantState(db.DB.WithContext(ctx), tenantID, to); err != nil {
+		return gmterrors.New(fmt.Errorf("failed to transition tenant %s to %q: %w", tenantID, to, err))
+	}
+	return nil
+}
+
+// TenantsByState returns the identifiers of the tenants in any of the given lifecycle states,
+// ordered by identifier. Tenants whose lifecycle is not tracked are not included.
+func (db *DB) TenantsByState(ctx context.Context, states ...migrator.TenantState) ([]string, error) {
+	tenantIDs, err := migrator.ListTenantsByState(db.DB.WithContext(ctx), states...)
+	if err != nil {
+		return nil, gmterrors.New(fmt.Errorf("failed to list tenants by state: %w", err))
+	}
+	return tenantIDs, nil
+}
+
+// tracksLifecycle reports whether the lifecycle of tenants is tracked, which requires a dialector
+// exposing its registered models.
+func (db *DB) tracksLifecycle() bool {
+	return db.modelRegistry() != nil
+}
+
+// migrationWait configures how long a migration waits for a concurrent migration of the same
+// tenant to end.
+var migrationWait = []backoff.Option{backoff.WithMaxRetries(10), backoff.WithRetryInterval(time.Second)}
+
+// beginTenantMigration transitions the tenant to [migrator.TenantMigrating], and returns a
+// function restoring its previous state, or [migrator.TenantActive] if it was not tracked. If the
+// tenant is being migrated concurrently, it waits for the other migration to end first (see
+// migrationWait), so that the state recorded before either migration is restored.
+func (db *DB) beginTenantMigration(ctx context.Context, tenantID string) (end func() error, err error) {
+	if !db.tracksLifecycle() {
+		return func() error { return nil }, nil
+	}
+	var from migrator.TenantState
+	retryErr := backoff.Retry(func() error {
+		from, err = migrator.TransitionTenantState(db.DB.WithContext(ctx), tenantID, migrator.TenantMigrating)
+		if errors.Is(err, migrator.ErrInvalidTransition) && from == migrator.TenantMigrating && ctx.Err() == nil {
+			return err
+		}
+		return nil
+	}, migrationWait...)
+	err = cmp.Or(err, retryErr)
+	if err != nil {
+		return nil, gmterrors.New(fmt.Errorf("failed to transition tenant %s to %q: %w", tenantID, migrator.TenantMigrating, err))
+	}
+	return func() error {
+		return db.TransitionTenant(context.WithoutCancel(ctx), tenantID, cmp.Or(from, migrator.TenantActive))
+	}, nil
+}
+
+// planTenantMigration plans the migration of the tenant once, for the drivers to reuse (see
+// [migrator.WithTenantPlan]), and reports whether the tables of the tenant match the registered
+// tenant models, so that the migration can be skipped without changing the lifecycle state of the
+// tenant. It returns an error wrapping [migrator.ErrInvalidTransition] if the tenant cannot be
+// migrated in its current state, as the migration would have.
+func (db *DB) planTenantMigration(ctx context.Context, tx *gorm.DB, tenantID string) (*gorm.DB, bool, error) {
+	registry := db.modelRegistry()
+	if registry == nil || len(registry.RegisteredTenantModels()) == 0 {
+		return tx, false, nil
+	}
+	plan, err := migrator.PlanTenantMigration(tx.WithContext(ctx), registry, tenantID)
+	if err != nil {
+		return nil, false, gmterrors.New(fmt.Errorf("failed to plan migration for tenant %s: %w", tenantID, err))
+	}
+	if !plan.UpToDate {
+		return migrator.WithTenantPlan(plan)(tx), false, nil
+	}
+	state, err := migrator.LoadTenantState(tx.WithContext(ctx), tenantID)
+	if err != nil {
+		return nil, false, gmterrors.New(fmt.Errorf("failed to load state of tenant %s: %w", tenantID, err))
+	}
+	// A tenant that is being migrated concurrently is up to date as well.
+	if state != migrator.TenantMigrating && !state.CanTransition(migrator.TenantMigrating) {
+		return nil, false, gmterrors.New(fmt.Errorf("failed to transition tenant %s to %q: %w: tenant %s cannot transition from %q to %q",
+			tenantID, migrator.TenantMigrating, migrator.ErrInvalidTransition, tenantID, state, migrator.TenantMigrating))
+	}
+	return tx, true, nil
+}
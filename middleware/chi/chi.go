@@ -0,0 +1,142 @@
+/*
+Package chimiddleware provides a middleware for the chi router, which adds multi-tenancy support.
+
+Example usage:
+
+	import (
+	    "net/http"
+
+	    chimiddleware "github.com/bartventer/gorm-multitenancy/middleware/chi/v8"
+	    "github.com/bartventer/gorm-multitenancy/v8"
+	    "github.com/go-chi/chi/v5"
+	)
+
+	func main() {
+	    r := chi.NewRouter()
+
+	    r.Use(chimiddleware.WithTenant(chimiddleware.DefaultWithTenantConfig))
+
+	    r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+	        tenant := r.Context().Value(chimiddleware.TenantKey).(string)
+	        fmt.Fprintf(w, "Hello, %s", tenant)
+	    })
+
+	    http.ListenAndServe(":8080", r)
+	}
+
+# Resolving Tenants by Path
+
+For tenants routed by path, such as /t/{tenant}/books, either mount the routes under a pattern
+with a tenant parameter and use [TenantFromURLParam], or wrap the router with
+[StripTenantPathPrefix] and use [TenantFromPathPrefix], so that the routes need not be aware of
+the tenant.
+
+# Errors
+
+The default error handler, [ProblemErrorHandler], responds with the status code of the error
+returned by the tenant getters, such as 400 Bad Request for [ErrTenantMissing] or 404 Not Found
+for [ErrTenantNotFound], and an RFC 7807 application/problem+json body.
+
+# Request Transactions
+
+[WithTransaction] runs each request within a transaction scoped to its tenant. The transaction is
+committed when the handler succeeds, and rolled back when it fails or panics. Handlers retrieve
+the tenant-bound database with [DBFromContext].
+*/
+package chimiddleware
+
+import (
+	"fmt"
+	"net/http"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/go-chi/chi/v5"
+)
+
+// DefaultSkipper returns false which processes the middleware.
+func DefaultSkipper(r *http.Request) bool {
+	return nethttpmw.DefaultSkipper(r)
+}
+
+// DefaultTenantFromSubdomain extracts the subdomain from the given HTTP request's host.
+func DefaultTenantFromSubdomain(r *http.Request) (string, error) {
+	return nethttpmw.DefaultTenantFromSubdomain(r)
+}
+
+// DefaultTenantFromHeader extracts the tenant from the header in the HTTP request.
+func DefaultTenantFromHeader(r *http.Request) (string, error) {
+	return nethttpmw.DefaultTenantFromHeader(r)
+}
+
+// TenantFromURLParam returns a tenant getter that extracts the tenant from the URL parameter with
+// the given name, such as tenant in the route pattern /t/{tenant}/books.
+func TenantFromURLParam(name string) func(r *http.Request) (string, error) {
+	return func(r *http.Request) (string, error) {
+		tenant := chi.URLParam(r, name)
+		if tenant == "" {
+			return "", fmt.Errorf("%w: failed to get tenant from `%s` URL parameter, parameter is empty", ErrTenantMissing, name)
+		}
+		return tenant, nil
+	}
+}
+
+var (
+	// DefaultWithTenantConfig is the default configuration for the WithTenant middleware.
+	DefaultWithTenantConfig = WithTenantConfig{
+		Skipper: DefaultSkipper,
+		TenantGetters: []func(r *http.Request) (string, error){
+			DefaultTenantFromSubdomain,
+			DefaultTenantFromHeader,
+		},
+		ContextKey:   TenantKey,
+		ErrorHandler: ProblemErrorHandler,
+	}
+)
+
+// WithTenantConfig represents the configuration options for the tenant middleware in chi.
+type WithTenantConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper func(r *http.Request) bool
+
+	// TenantGetters is a list of functions that retrieve the tenant from the request.
+	// Each function should return the tenant as a string and an error if any.
+	// The functions are executed in order until a valid tenant is found.
+	TenantGetters []func(r *http.Request) (string, error)
+
+	// TrustedProxies are the proxies trusted to forward the original host of the request, which
+	// the tenant getters then use instead of the Host of the request; optional.
+	TrustedProxies *TrustedProxies
+
+	// ContextKey is the key used to store the tenant in the context.
+	ContextKey fmt.Stringer
+
+	// ErrorHandler is a callback function that is called when an error occurs during the tenant retrieval process.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+	// SuccessHandler is a callback function that is called after the tenant is successfully set in the request context.
+	// It can be used to perform additional operations, such as modifying the database connection based on the tenant.
+	SuccessHandler func(w http.ResponseWriter, r *http.Request)
+}
+
+// WithTenant is a middleware function that adds multi-tenancy support to a chi router. It calls
+// [nethttpmw.WithTenant] to retrieve the tenant and store it in the request context, under the
+// ContextKey and with tenant.NewContext.
+func WithTenant(config WithTenantConfig) func(http.Handler) http.Handler {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTenantConfig.Skipper
+	}
+
+	if len(config.TenantGetters) == 0 {
+		config.TenantGetters = DefaultWithTenantConfig.TenantGetters
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTenantConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTenantConfig.ErrorHandler
+	}
+
+	return nethttpmw.WithTenant(nethttpmw.WithTenantConfig(config))
+}
@@ -0,0 +1,182 @@
+package chimiddleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
+	"github.com/go-chi/chi/v5"
+)
+
+func assertEqual[T any](t *testing.T, expected, actual T) bool {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+		return false
+	}
+	return true
+}
+
+func ExampleWithTenant() {
+	r := chi.NewRouter()
+
+	r.Use(WithTenant(DefaultWithTenantConfig))
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Context().Value(TenantKey).(string)
+		fmt.Println("Tenant:", tenant)
+		fmt.Fprintf(w, "Hello, %s", tenant)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "tenant.example.com"
+	w := httptest.NewRecorder()
+
+	// Execute the request
+	r.ServeHTTP(w, req)
+
+	// Output: Tenant: tenant
+}
+
+func TestWithTenant(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		config   WithTenantConfig
+		wantCode int
+		want     string
+	}{
+		{
+			name:     "test-with-tenant-subdomain",
+			host:     "tenant1.example.com",
+			config:   WithTenantConfig{},
+			wantCode: http.StatusOK,
+			want:     "tenant1",
+		},
+		{
+			name: "test-with-skipper",
+			host: "example.com",
+			config: WithTenantConfig{
+				Skipper: func(r *http.Request) bool { return true },
+			},
+			wantCode: http.StatusOK,
+			want:     "",
+		},
+		{
+			name: "test-with-error-handler",
+			host: "tenant1.example.com",
+			config: WithTenantConfig{
+				TenantGetters: []func(r *http.Request) (string, error){
+					func(r *http.Request) (string, error) {
+						return "", errors.New("forced error")
+					},
+				},
+				ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+				},
+			},
+			wantCode: http.StatusBadRequest,
+			want:     "forced error\n",
+		},
+		{
+			name:     "test-with-default-error-handler",
+			host:     "example.com",
+			config:   WithTenantConfig{},
+			wantCode: http.StatusBadRequest,
+			want:     `{"type":"about:blank","title":"Bad Request","status":400,"detail":"tenant missing","instance":"/"}` + "\n",
+		},
+		{
+			name: "test-with-success-handler",
+			host: "tenant1.example.com",
+			config: WithTenantConfig{
+				SuccessHandler: func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("X-Success", "true")
+				},
+			},
+			wantCode: http.StatusOK,
+			want:     "tenant1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(WithTenant(tt.config))
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				tenant, _ := r.Context().Value(TenantKey).(string)
+				_, _ = w.Write([]byte(tenant))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tt.host
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assertEqual(t, tt.wantCode, w.Code)
+			assertEqual(t, tt.want, w.Body.String())
+			if tt.config.SuccessHandler != nil {
+				assertEqual(t, "true", w.Header().Get("X-Success"))
+			}
+		})
+	}
+}
+
+func TestTenantFromURLParam(t *testing.T) {
+	r := chi.NewRouter()
+	r.Route("/t/{tenant}", func(r chi.Router) {
+		r.Use(WithTenant(WithTenantConfig{
+			TenantGetters: []func(r *http.Request) (string, error){TenantFromURLParam("tenant")},
+		}))
+		r.Get("/books", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.Context().Value(TenantKey).(string)))
+		})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/t/acme/books", nil))
+	assertEqual(t, http.StatusOK, w.Code)
+	assertEqual(t, "acme", w.Body.String())
+
+	_, err := TenantFromURLParam("tenant")(httptest.NewRequest(http.MethodGet, "/", nil))
+	assertEqual(t, true, errors.Is(err, ErrTenantInvalid))
+}
+
+func TestTenantFromPathPrefix(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(WithTenant(WithTenantConfig{
+		TenantGetters: []func(r *http.Request) (string, error){TenantFromPathPrefix("/t")},
+	}))
+	r.Get("/books", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Context().Value(TenantKey).(string)))
+	})
+	handler := StripTenantPathPrefix("/t")(r)
+
+	for target, want := range map[string]int{"/t/acme/books": http.StatusOK, "/books": http.StatusBadRequest} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assertEqual(t, want, w.Code)
+		if want == http.StatusOK {
+			assertEqual(t, "acme", w.Body.String())
+		}
+	}
+}
+
+func TestWithTenant_tenantContext(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(WithTenant(DefaultWithTenantConfig))
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		got, _ := tenant.FromContext(r.Context())
+		fmt.Fprint(w, got)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "acme.example.com"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assertEqual(t, http.StatusOK, w.Code)
+	assertEqual(t, "acme", w.Body.String())
+}
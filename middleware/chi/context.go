@@ -0,0 +1,14 @@
+package chimiddleware
+
+type contextKey struct {
+	name string
+}
+
+func (c contextKey) String() string {
+	return "gorm-multitenancy/middleware/chi/" + c.name
+}
+
+var (
+	// TenantKey is the key that holds the tenant in a request context.
+	TenantKey = &contextKey{"tenant"}
+)
@@ -0,0 +1,16 @@
+package chimiddleware
+
+import nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+
+// ProblemContentType is an alias for [nethttpmw.ProblemContentType].
+const ProblemContentType = nethttpmw.ProblemContentType
+
+// Problem is an alias for [nethttpmw.Problem].
+type Problem = nethttpmw.Problem
+
+// NewProblem is an alias for [nethttpmw.NewProblem].
+var NewProblem = nethttpmw.NewProblem
+
+// ProblemErrorHandler is an alias for [nethttpmw.ProblemErrorHandler]. It is the default error
+// handler of [WithTenant].
+var ProblemErrorHandler = nethttpmw.ProblemErrorHandler
@@ -0,0 +1,24 @@
+module github.com/bartventer/gorm-multitenancy/middleware/chi/v8
+
+go 1.24
+
+replace github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8 => ../nethttp
+
+replace github.com/bartventer/gorm-multitenancy/v8 => ../../
+
+require (
+	github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8 v8.8.1
+	github.com/bartventer/gorm-multitenancy/v8 v8.8.1
+	github.com/go-chi/chi/v5 v5.3.2
+	gorm.io/gorm v1.30.0
+)
+
+require (
+	github.com/go-viper/mapstructure/v2 v2.3.0 // indirect
+	github.com/golang-jwt/jwt/v5 v5.3.1 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
@@ -0,0 +1,13 @@
+package chimiddleware
+
+import nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+
+// DomainLookup is an alias for [nethttpmw.DomainLookup]. Its TenantFromRequest method is a tenant
+// getter.
+type DomainLookup = nethttpmw.DomainLookup
+
+// NewDomainLookup is an alias for [nethttpmw.NewDomainLookup].
+var NewDomainLookup = nethttpmw.NewDomainLookup
+
+// NewDomainLookupFunc is an alias for [nethttpmw.NewDomainLookupFunc].
+var NewDomainLookupFunc = nethttpmw.NewDomainLookupFunc
@@ -0,0 +1,19 @@
+package chimiddleware
+
+import nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+
+// ExtractTenantFromPath is an alias for [nethttpmw.ExtractTenantFromPath].
+var ExtractTenantFromPath = nethttpmw.ExtractTenantFromPath
+
+// TenantFromPathPrefix is an alias for [nethttpmw.TenantFromPathPrefix].
+var TenantFromPathPrefix = nethttpmw.TenantFromPathPrefix
+
+// StripTenantPathPrefix is an alias for [nethttpmw.StripTenantPathPrefix]. It wraps the router,
+// so that the routes need not be aware of the tenant:
+//
+//	r.Use(WithTenant(WithTenantConfig{
+//		TenantGetters: []func(r *http.Request) (string, error){TenantFromPathPrefix("/t")},
+//	}))
+//	r.Get("/books", listBooks) // serves /t/{tenant}/books
+//	http.ListenAndServe(":8080", StripTenantPathPrefix("/t")(r))
+var StripTenantPathPrefix = nethttpmw.StripTenantPathPrefix
@@ -0,0 +1,20 @@
+package chimiddleware
+
+import nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+
+// ExtractSubdomain is an alias for [nethttpmw.ExtractSubdomain].
+var ExtractSubdomain = nethttpmw.ExtractSubdomain
+
+// SubdomainOption is an alias for [nethttpmw.SubdomainOption].
+type SubdomainOption = nethttpmw.SubdomainOption
+
+var (
+	// WithBaseDomains is an alias for [nethttpmw.WithBaseDomains].
+	WithBaseDomains = nethttpmw.WithBaseDomains
+
+	// WithPublicSuffixList is an alias for [nethttpmw.WithPublicSuffixList].
+	WithPublicSuffixList = nethttpmw.WithPublicSuffixList
+)
+
+// TenantFromSubdomain is an alias for [nethttpmw.TenantFromSubdomain].
+var TenantFromSubdomain = nethttpmw.TenantFromSubdomain
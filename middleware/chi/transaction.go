@@ -0,0 +1,82 @@
+package chimiddleware
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+)
+
+// DefaultReadOnly is an alias for [nethttpmw.DefaultReadOnly].
+var DefaultReadOnly = nethttpmw.DefaultReadOnly
+
+// DBFromContext is an alias for [nethttpmw.DBFromContext].
+var DBFromContext = nethttpmw.DBFromContext
+
+// DefaultWithTransactionConfig is the default configuration for the WithTransaction middleware.
+// It uses the default skipper and context key, responds to errors with
+// [ProblemErrorHandler], and opens read-write transactions for all requests.
+var DefaultWithTransactionConfig = WithTransactionConfig{
+	Skipper:      DefaultSkipper,
+	ContextKey:   TenantKey,
+	ErrorHandler: nethttpmw.DefaultWithTransactionConfig.ErrorHandler,
+}
+
+// WithTransactionConfig represents the configuration options for the transaction middleware in
+// chi.
+type WithTransactionConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper func(r *http.Request) bool
+
+	// ContextKey is the key of the tenant in the request context, as set by [WithTenant].
+	ContextKey fmt.Stringer
+
+	// TxOptions are the options of the transaction; optional.
+	TxOptions *sql.TxOptions
+
+	// ReadOnly reports whether the transaction of the request should be read-only, such as
+	// [DefaultReadOnly]; optional.
+	ReadOnly func(r *http.Request) bool
+
+	// ErrorHandler is a callback function that is called when the transaction cannot be opened or
+	// committed. The response has not been written then, and the response of the handler is
+	// discarded.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// WithTransaction is a middleware function that runs each request within a transaction scoped to
+// the tenant of the request, as set by [WithTenant], which must therefore run first. It calls
+// [nethttpmw.WithTransaction] to manage the transaction; see there for when the transaction is
+// committed.
+//
+// Example:
+//
+//	r.Use(chimw.WithTenant(chimw.DefaultWithTenantConfig))
+//	r.Use(chimw.WithTransaction(db, chimw.WithTransactionConfig{ReadOnly: chimw.DefaultReadOnly}))
+//
+//	r.Get("/books", func(w http.ResponseWriter, r *http.Request) {
+//		tx, _ := chimw.DBFromContext(r.Context())
+//		var books []Book
+//		if err := tx.Find(&books).Error; err != nil {
+//			http.Error(w, err.Error(), http.StatusInternalServerError)
+//			return
+//		}
+//		...
+//	})
+func WithTransaction(db *multitenancy.DB, config WithTransactionConfig) func(http.Handler) http.Handler {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTransactionConfig.Skipper
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTransactionConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTransactionConfig.ErrorHandler
+	}
+
+	return nethttpmw.WithTransaction(db, nethttpmw.WithTransactionConfig(config))
+}
@@ -0,0 +1,98 @@
+package chimiddleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+)
+
+// fakeConnPool is a [gorm.ConnPool] recording the transactions committed and rolled back.
+type fakeConnPool struct {
+	gorm.ConnPool
+	committed, rolled int
+}
+
+func (p *fakeConnPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &fakeTx{pool: p}, nil
+}
+
+type fakeTx struct {
+	gorm.ConnPool
+	pool *fakeConnPool
+}
+
+func (t *fakeTx) Commit() error   { t.pool.committed++; return nil }
+func (t *fakeTx) Rollback() error { t.pool.rolled++; return nil }
+
+type fakeDriver struct{}
+
+func (fakeDriver) RegisterModels(context.Context, *gorm.DB, ...driver.TenantTabler) error { return nil }
+func (fakeDriver) MigrateSharedModels(context.Context, *gorm.DB) error                    { return nil }
+func (fakeDriver) MigrateTenantModels(context.Context, *gorm.DB, string) error            { return nil }
+func (fakeDriver) OffboardTenant(context.Context, *gorm.DB, string) error                 { return nil }
+func (fakeDriver) CurrentTenant(context.Context, *gorm.DB) string                         { return "" }
+func (fakeDriver) UseTenant(context.Context, *gorm.DB, string) (func() error, error) {
+	return func() error { return nil }, nil
+}
+
+func newFakeDB(t *testing.T) (*multitenancy.DB, *fakeConnPool) {
+	t.Helper()
+	pool := new(fakeConnPool)
+	gdb, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{ConnPool: pool, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return multitenancy.NewDB(fakeDriver{}, gdb), pool
+}
+
+func TestWithTransaction(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		wantCommit   bool
+		wantRollback bool
+	}{
+		{name: "commit", handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusCreated) }, wantCommit: true},
+		{name: "rollback on status", handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusConflict) }, wantRollback: true},
+		{name: "rollback on panic", handler: func(w http.ResponseWriter, r *http.Request) { panic("boom") }, wantRollback: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, pool := newFakeDB(t)
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					defer func() {
+						if recover() != nil {
+							w.WriteHeader(http.StatusInternalServerError)
+						}
+					}()
+					next.ServeHTTP(w, r)
+				})
+			})
+			r.Use(WithTenant(DefaultWithTenantConfig))
+			r.Use(WithTransaction(db, WithTransactionConfig{}))
+			r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+				if _, ok := DBFromContext(r.Context()); !ok {
+					t.Error("DBFromContext() ok = false, want true")
+				}
+				tt.handler(w, r)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Host = "tenant1.example.com"
+			r.ServeHTTP(httptest.NewRecorder(), req)
+			assertEqual(t, tt.wantCommit, pool.committed == 1)
+			assertEqual(t, tt.wantRollback, pool.rolled == 1)
+		})
+	}
+}
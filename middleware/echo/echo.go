@@ -28,9 +28,9 @@ package echo
 
 import (
 	"fmt"
-	"net/http"
 
 	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	tenantctx "github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
 	"github.com/labstack/echo/v4"
 )
 
@@ -61,10 +61,8 @@ var (
 			DefaultTenantFromSubdomain,
 			DefaultTenantFromHeader,
 		},
-		ContextKey: TenantKey,
-		ErrorHandler: func(c echo.Context, _ error) error {
-			return echo.NewHTTPError(http.StatusInternalServerError, nethttpmw.ErrTenantInvalid.Error())
-		},
+		ContextKey:   TenantKey,
+		ErrorHandler: ProblemErrorHandler,
 	}
 )
 
@@ -79,6 +77,10 @@ type WithTenantConfig struct {
 	// The functions are executed in order until a valid tenant is found.
 	TenantGetters []func(c echo.Context) (string, error)
 
+	// TrustedProxies are the proxies trusted to forward the original host of the request, which
+	// the tenant getters then use instead of the Host of the request; optional.
+	TrustedProxies *TrustedProxies
+
 	// ContextKey is the key used to store the tenant in the context.
 	ContextKey fmt.Stringer
 
@@ -96,7 +98,8 @@ type WithTenantConfig struct {
 // The middleware checks if the request should be skipped based on the Skipper function.
 // It retrieves the tenant information using the TenantGetters functions.
 // If an error occurs while retrieving the tenant, the ErrorHandler function is called.
-// The retrieved tenant is then set in the request context using the ContextKey.
+// The retrieved tenant is then set in the Echo context using the ContextKey, and in the context of
+// the request with [tenantctx.NewContext].
 // Finally, the SuccessHandler function is called if provided, and the next handler is invoked.
 func WithTenant(config WithTenantConfig) echo.MiddlewareFunc {
 	if config.Skipper == nil {
@@ -120,21 +123,16 @@ func WithTenant(config WithTenantConfig) echo.MiddlewareFunc {
 			if config.Skipper(c) {
 				return next(c)
 			}
-			var (
-				tenant string
-				err    error
-			)
-			for _, getter := range config.TenantGetters {
-				tenant, err = getter(c)
-				if err == nil {
-					break
-				}
+			if config.TrustedProxies != nil {
+				c.SetRequest(nethttpmw.ResolveRequestHost(c.Request(), config.TrustedProxies))
 			}
+			tenant, err := nethttpmw.ResolveTenant(c, config.TenantGetters)
 			if err != nil {
 				return config.ErrorHandler(c, err)
 			}
 			// set tenant in request context
 			c.Set(config.ContextKey.String(), tenant)
+			c.SetRequest(c.Request().WithContext(tenantctx.NewContext(c.Request().Context(), tenantctx.Tenant(tenant))))
 
 			// call success handler
 			if config.SuccessHandler != nil {
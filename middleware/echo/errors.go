@@ -0,0 +1,28 @@
+package echo
+
+import (
+	"encoding/json"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/labstack/echo/v4"
+)
+
+// ProblemContentType is an alias for [nethttpmw.ProblemContentType].
+const ProblemContentType = nethttpmw.ProblemContentType
+
+// Problem is an alias for [nethttpmw.Problem].
+type Problem = nethttpmw.Problem
+
+// NewProblem is an alias for [nethttpmw.NewProblem].
+var NewProblem = nethttpmw.NewProblem
+
+// ProblemErrorHandler is an error handler that responds with the [Problem] of the error, as
+// application/problem+json. It is the default error handler of [WithTenant].
+func ProblemErrorHandler(c echo.Context, err error) error {
+	p := nethttpmw.NewProblem(err, c.Request().URL.Path)
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return c.Blob(p.Status, ProblemContentType, b)
+}
@@ -4,12 +4,19 @@ go 1.24
 
 replace github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8 => ../nethttp
 
+replace github.com/bartventer/gorm-multitenancy/v8 => ../../
+
 require (
 	github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8 v8.8.1
+	github.com/bartventer/gorm-multitenancy/v8 v8.8.1
 	github.com/labstack/echo/v4 v4.13.4
 )
 
 require (
+	github.com/go-viper/mapstructure/v2 v2.3.0 // indirect
+	github.com/golang-jwt/jwt/v5 v5.3.1 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
 	github.com/labstack/gommon v0.4.2 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
@@ -17,6 +24,8 @@ require (
 	github.com/valyala/fasttemplate v1.2.2 // indirect
 	golang.org/x/crypto v0.40.0 // indirect
 	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
+	gorm.io/gorm v1.30.0 // indirect
 )
@@ -0,0 +1,24 @@
+package echo
+
+import (
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/labstack/echo/v4"
+)
+
+// DomainLookup is an alias for [nethttpmw.DomainLookup].
+type DomainLookup = nethttpmw.DomainLookup
+
+// NewDomainLookup is an alias for [nethttpmw.NewDomainLookup].
+var NewDomainLookup = nethttpmw.NewDomainLookup
+
+// NewDomainLookupFunc is an alias for [nethttpmw.NewDomainLookupFunc].
+var NewDomainLookupFunc = nethttpmw.NewDomainLookupFunc
+
+// TenantFromDomainLookup returns a tenant getter that resolves the tenant by looking up the host
+// of the request with the given [DomainLookup]. It calls [nethttpmw.DomainLookup.TenantFromRequest]
+// to look up the host.
+func TenantFromDomainLookup(lookup *DomainLookup) func(c echo.Context) (string, error) {
+	return func(c echo.Context) (string, error) {
+		return lookup.TenantFromRequest(c.Request())
+	}
+}
@@ -0,0 +1,28 @@
+package echo
+
+import (
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/labstack/echo/v4"
+)
+
+// ExtractTenantFromPath is an alias for [nethttpmw.ExtractTenantFromPath].
+var ExtractTenantFromPath = nethttpmw.ExtractTenantFromPath
+
+// StripTenantPathPrefix is an alias for [nethttpmw.StripTenantPathPrefix]. It wraps the
+// application, so that the routes need not be aware of the tenant:
+//
+//	e.Use(WithTenant(WithTenantConfig{
+//		TenantGetters: []func(c echo.Context) (string, error){TenantFromPathPrefix("/t")},
+//	}))
+//	e.GET("/books", listBooks) // serves /t/{tenant}/books
+//	http.ListenAndServe(":8080", StripTenantPathPrefix("/t")(e))
+var StripTenantPathPrefix = nethttpmw.StripTenantPathPrefix
+
+// TenantFromPathPrefix returns a tenant getter that extracts the tenant from the path segment
+// following the prefix. It calls [nethttpmw.TenantFromPathPrefix] to extract the tenant.
+func TenantFromPathPrefix(prefix string) func(c echo.Context) (string, error) {
+	getter := nethttpmw.TenantFromPathPrefix(prefix)
+	return func(c echo.Context) (string, error) {
+		return getter(c.Request())
+	}
+}
@@ -1,6 +1,30 @@
 package echo
 
-import nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+import (
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/labstack/echo/v4"
+)
 
 // ExtractSubdomain is an alias for [nethttpmw.ExtractSubdomain].
 var ExtractSubdomain = nethttpmw.ExtractSubdomain
+
+// SubdomainOption is an alias for [nethttpmw.SubdomainOption].
+type SubdomainOption = nethttpmw.SubdomainOption
+
+var (
+	// WithBaseDomains is an alias for [nethttpmw.WithBaseDomains].
+	WithBaseDomains = nethttpmw.WithBaseDomains
+
+	// WithPublicSuffixList is an alias for [nethttpmw.WithPublicSuffixList].
+	WithPublicSuffixList = nethttpmw.WithPublicSuffixList
+)
+
+// TenantFromSubdomain returns a tenant getter that extracts the tenant from the subdomain of the
+// request host with the given options, such as [WithBaseDomains]. It calls
+// [nethttpmw.TenantFromSubdomain] to extract the tenant.
+func TenantFromSubdomain(opts ...SubdomainOption) func(c echo.Context) (string, error) {
+	getter := nethttpmw.TenantFromSubdomain(opts...)
+	return func(c echo.Context) (string, error) {
+		return getter(c.Request())
+	}
+}
@@ -0,0 +1,131 @@
+package echo
+
+import (
+	"database/sql"
+	"fmt"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultReadOnly reports whether the request only reads data, based on its method (GET or HEAD).
+// It calls the default [nethttpmw.DefaultReadOnly] function.
+func DefaultReadOnly(c echo.Context) bool {
+	return nethttpmw.DefaultReadOnly(c.Request())
+}
+
+// DefaultWithTransactionConfig is the default configuration for the WithTransaction middleware.
+// It uses the default skipper and context key, responds to errors with [ProblemErrorHandler], and
+// opens read-write transactions for all requests.
+var DefaultWithTransactionConfig = WithTransactionConfig{
+	Skipper:      DefaultSkipper,
+	ContextKey:   TenantKey,
+	ErrorHandler: ProblemErrorHandler,
+}
+
+// WithTransactionConfig represents the configuration options for the transaction middleware in
+// Echo.
+type WithTransactionConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper func(c echo.Context) bool
+
+	// ContextKey is the key of the tenant in the echo context, as set by [WithTenant].
+	ContextKey fmt.Stringer
+
+	// TxOptions are the options of the transaction; optional.
+	TxOptions *sql.TxOptions
+
+	// ReadOnly reports whether the transaction of the request should be read-only, such as
+	// [DefaultReadOnly]; optional.
+	ReadOnly func(c echo.Context) bool
+
+	// ErrorHandler is a callback function that is called when the transaction cannot be opened or
+	// committed. The response has not been written then, and the response of the handler is
+	// discarded.
+	ErrorHandler func(c echo.Context, err error) error
+}
+
+// WithTransaction is a middleware function that runs each request within a transaction scoped to
+// the tenant of the request, as set by [WithTenant], which must therefore run first. The
+// tenant-bound database is stored in the echo context, and retrieved with [DBFromContext].
+//
+// The transaction is committed if the handler returns no error and responds with a 2xx or 3xx
+// status code, and rolled back otherwise, or if the handler panics. The panic is propagated after
+// the rollback. The transaction ends before the status line of the response is written, so that
+// the client is not told that the request succeeded if the commit fails: the response of the
+// handler is then discarded, and the error handler responds instead.
+//
+// Example:
+//
+//	e.Use(echomw.WithTenant(echomw.DefaultWithTenantConfig))
+//	e.Use(echomw.WithTransaction(db, echomw.WithTransactionConfig{ReadOnly: echomw.DefaultReadOnly}))
+//
+//	e.GET("/books", func(c echo.Context) error {
+//		tx, _ := echomw.DBFromContext(c)
+//		var books []Book
+//		if err := tx.Find(&books).Error; err != nil {
+//			return err
+//		}
+//		return c.JSON(http.StatusOK, books)
+//	})
+func WithTransaction(db *multitenancy.DB, config WithTransactionConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTransactionConfig.Skipper
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTransactionConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTransactionConfig.ErrorHandler
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			tenant, _ := c.Get(config.ContextKey.String()).(string)
+			readOnly := config.ReadOnly != nil && config.ReadOnly(c)
+			tx, err := nethttpmw.BeginTenantTx(c.Request().Context(), db, tenant, config.TxOptions, readOnly)
+			if err != nil {
+				return config.ErrorHandler(c, err)
+			}
+			defer func() {
+				if p := recover(); p != nil {
+					_ = tx.Rollback()
+					panic(p)
+				}
+			}()
+
+			res := c.Response()
+			w := res.Writer
+			var tw *nethttpmw.TxResponseWriter
+			tw = nethttpmw.NewTxResponseWriter(w, tx, nil, func(err error) {
+				// Respond through the underlying writer; later writes of the handler fail.
+				res.Writer = w
+				defer func() { res.Writer = tw }()
+				if err := config.ErrorHandler(c, err); err != nil {
+					c.Error(err)
+				}
+			})
+			res.Writer = tw
+
+			c.Set(DBKey.String(), tx.DB)
+			if err := next(c); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+			_ = tw.End(res.Status) // the handler may have written no response
+			return nil
+		}
+	}
+}
+
+// DBFromContext returns the tenant-bound database stored in the echo context by [WithTransaction].
+func DBFromContext(c echo.Context) (*multitenancy.DB, bool) {
+	db, ok := c.Get(DBKey.String()).(*multitenancy.DB)
+	return db, ok
+}
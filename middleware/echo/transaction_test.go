@@ -0,0 +1,91 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/drivertest/fakedb"
+	"github.com/labstack/echo/v4"
+)
+
+func TestWithTransaction(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      echo.HandlerFunc
+		wantCommit   bool
+		wantRollback bool
+	}{
+		{name: "commit", handler: func(c echo.Context) error { return c.NoContent(http.StatusCreated) }, wantCommit: true},
+		{name: "rollback on status", handler: func(c echo.Context) error { return c.NoContent(http.StatusConflict) }, wantRollback: true},
+		{name: "rollback on error", handler: func(c echo.Context) error { return echo.ErrBadRequest }, wantRollback: true},
+		{name: "rollback on panic", handler: func(c echo.Context) error { panic("boom") }, wantRollback: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _, pool := fakedb.New(t)
+			e := echo.New()
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) (err error) {
+					defer func() {
+						if p := recover(); p != nil {
+							err = echo.ErrInternalServerError
+						}
+					}()
+					return next(c)
+				}
+			})
+			e.Use(WithTenant(DefaultWithTenantConfig))
+			e.Use(WithTransaction(db, WithTransactionConfig{}))
+			e.POST("/", func(c echo.Context) error {
+				if _, ok := DBFromContext(c); !ok {
+					t.Error("DBFromContext() ok = false, want true")
+				}
+				return tt.handler(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Host = "tenant1.example.com"
+			e.ServeHTTP(httptest.NewRecorder(), req)
+			assertEqual(t, tt.wantCommit, pool.Committed == 1)
+			assertEqual(t, tt.wantRollback, pool.RolledBack == 1)
+		})
+	}
+}
+
+func TestWithTransaction_CommitError(t *testing.T) {
+	db, _, pool := fakedb.New(t)
+	pool.CommitErr = errors.New("serialization failure")
+	e := echo.New()
+	e.Use(WithTenant(DefaultWithTenantConfig))
+	e.Use(WithTransaction(db, WithTransactionConfig{}))
+	e.POST("/", func(c echo.Context) error {
+		return c.String(http.StatusCreated, "created")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Host = "tenant1.example.com"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assertEqual(t, http.StatusInternalServerError, rec.Code)
+	assertEqual(t, ProblemContentType, rec.Header().Get(echo.HeaderContentType))
+	if strings.Contains(rec.Body.String(), "created") {
+		t.Errorf("body = %q, want the response of the handler discarded", rec.Body.String())
+	}
+}
+
+func TestWithTransaction_NoTenant(t *testing.T) {
+	db, _, _ := fakedb.New(t)
+	e := echo.New()
+	e.Use(WithTransaction(db, WithTransactionConfig{}))
+	e.POST("/", func(c echo.Context) error {
+		t.Error("handler called")
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assertEqual(t, http.StatusBadRequest, rec.Code)
+}
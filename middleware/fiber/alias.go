@@ -0,0 +1,24 @@
+package fibermiddleware
+
+import "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+
+// XTenantHeader is an alias for [nethttp.XTenantHeader].
+const XTenantHeader = nethttp.XTenantHeader
+
+// ErrTenantInvalid is an alias for [nethttp.ErrTenantInvalid].
+var ErrTenantInvalid = nethttp.ErrTenantInvalid
+
+// Tenant errors, aliases for [nethttp.ErrTenantMissing], [nethttp.ErrTenantNotFound],
+// [nethttp.ErrTenantForbidden] and [nethttp.ErrTenantSuspended].
+var (
+	ErrTenantMissing   = nethttp.ErrTenantMissing
+	ErrTenantNotFound  = nethttp.ErrTenantNotFound
+	ErrTenantForbidden = nethttp.ErrTenantForbidden
+	ErrTenantSuspended = nethttp.ErrTenantSuspended
+)
+
+// TenantError is an alias for [nethttp.TenantError].
+type TenantError = nethttp.TenantError
+
+// StatusCode is an alias for [nethttp.StatusCode].
+var StatusCode = nethttp.StatusCode
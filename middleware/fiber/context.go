@@ -0,0 +1,17 @@
+package fibermiddleware
+
+type contextKey struct {
+	name string
+}
+
+func (c contextKey) String() string {
+	return "gorm-multitenancy/middleware/fiber/" + c.name
+}
+
+var (
+	// TenantKey is the key that holds the tenant in a request context.
+	TenantKey = &contextKey{"tenant"}
+
+	// DBKey is the key that holds the tenant-bound database in a request context.
+	DBKey = &contextKey{"db"}
+)
@@ -0,0 +1,22 @@
+package fibermiddleware
+
+import (
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProblemContentType is an alias for [nethttpmw.ProblemContentType].
+const ProblemContentType = nethttpmw.ProblemContentType
+
+// Problem is an alias for [nethttpmw.Problem].
+type Problem = nethttpmw.Problem
+
+// NewProblem is an alias for [nethttpmw.NewProblem].
+var NewProblem = nethttpmw.NewProblem
+
+// ProblemErrorHandler is an error handler that responds with the [Problem] of the error, as
+// application/problem+json. It is the default error handler of [WithTenant].
+func ProblemErrorHandler(c *fiber.Ctx, err error) error {
+	p := nethttpmw.NewProblem(err, c.Path())
+	return c.Status(p.Status).JSON(p, ProblemContentType)
+}
@@ -0,0 +1,161 @@
+/*
+Package fibermiddleware provides a middleware for the Fiber framework, which adds multi-tenancy support.
+
+Example usage:
+
+	import (
+	    fibermiddleware "github.com/bartventer/gorm-multitenancy/middleware/fiber/v8"
+	    "github.com/bartventer/gorm-multitenancy/v8"
+	    "github.com/gofiber/fiber/v2"
+	)
+
+	func main() {
+	    app := fiber.New()
+
+	    app.Use(fibermiddleware.WithTenant(fibermiddleware.DefaultWithTenantConfig))
+
+	    app.Get("/", func(c *fiber.Ctx) error {
+	        tenant := c.Locals(fibermiddleware.TenantKey.String()).(string)
+	        return c.SendString("Hello, " + tenant)
+	    })
+
+	    app.Listen(":8080")
+	}
+
+Fiber is built on fasthttp rather than net/http, so unlike the other framework packages, the
+tenant getters of this package read the request from the Fiber context directly.
+
+The tenant getters take the host from Ctx.Hostname, which reads the X-Forwarded-Host header of
+requests from trusted proxies. Set EnableTrustedProxyCheck and TrustedProxies in the fiber.Config
+of the application, as Fiber otherwise trusts the header of any client.
+
+# Resolving Tenants by Path
+
+For tenants routed by path, such as /t/{tenant}/books, use [TenantFromPathPrefix]. Register
+[StripTenantPathPrefix] before the routes to remove the prefix and the tenant from the path, so
+that the routes need not be aware of the tenant.
+
+# Errors
+
+The default error handler, [ProblemErrorHandler], responds with the status code of the error
+returned by the tenant getters, such as 400 Bad Request for [ErrTenantMissing] or 404 Not Found
+for [ErrTenantNotFound], and an RFC 7807 application/problem+json body.
+
+# Tenant Context
+
+[WithTenant] also stores the tenant in the user context of the request with
+[tenantctx.NewContext], so that code shared across routers, such as a service layer, can retrieve
+it from c.UserContext() with tenant.FromContext.
+
+# Request Transactions
+
+[WithTransaction] runs each request within a transaction scoped to its tenant. The transaction is
+committed when the handler succeeds, and rolled back when it fails or panics. Handlers retrieve
+the tenant-bound database with [DBFromContext].
+*/
+package fibermiddleware
+
+import (
+	"fmt"
+	"strings"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	tenantctx "github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultSkipper returns false which processes the middleware.
+func DefaultSkipper(c *fiber.Ctx) bool {
+	return false
+}
+
+// DefaultTenantFromSubdomain extracts the subdomain from the given HTTP request's host.
+func DefaultTenantFromSubdomain(c *fiber.Ctx) (string, error) {
+	return TenantFromSubdomain()(c)
+}
+
+// DefaultTenantFromHeader extracts the tenant from the header in the HTTP request.
+func DefaultTenantFromHeader(c *fiber.Ctx) (string, error) {
+	tenant := strings.TrimSpace(c.Get(XTenantHeader))
+	if tenant == "" {
+		return "", fmt.Errorf("%w: failed to get tenant from `%s` header, header is empty", ErrTenantMissing, XTenantHeader)
+	}
+	return tenant, nil
+}
+
+var (
+	// DefaultWithTenantConfig is the default configuration for the WithTenant middleware.
+	DefaultWithTenantConfig = WithTenantConfig{
+		Skipper: DefaultSkipper,
+		TenantGetters: []func(c *fiber.Ctx) (string, error){
+			DefaultTenantFromSubdomain,
+			DefaultTenantFromHeader,
+		},
+		ContextKey:   TenantKey,
+		ErrorHandler: ProblemErrorHandler,
+	}
+)
+
+// WithTenantConfig represents the configuration options for the tenant middleware in Fiber.
+type WithTenantConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper func(c *fiber.Ctx) bool
+
+	// TenantGetters is a list of functions that retrieve the tenant from the request.
+	// Each function should return the tenant as a string and an error if any.
+	// The functions are executed in order until a valid tenant is found.
+	TenantGetters []func(c *fiber.Ctx) (string, error)
+
+	// ContextKey is the key used to store the tenant in the context.
+	ContextKey fmt.Stringer
+
+	// ErrorHandler is a callback function that is called when an error occurs during the tenant retrieval process.
+	ErrorHandler func(c *fiber.Ctx, err error) error
+
+	// SuccessHandler is a callback function that is called after the tenant is successfully set in the Fiber context.
+	// It can be used to perform additional operations, such as modifying the database connection based on the tenant.
+	SuccessHandler func(c *fiber.Ctx)
+}
+
+// WithTenant is a middleware function that adds multi-tenancy support to a Fiber application. The
+// tenant is stored in the locals of the Fiber context, under the string form of the ContextKey,
+// and in the user context with [tenantctx.NewContext].
+func WithTenant(config WithTenantConfig) fiber.Handler {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTenantConfig.Skipper
+	}
+
+	if len(config.TenantGetters) == 0 {
+		config.TenantGetters = DefaultWithTenantConfig.TenantGetters
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTenantConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTenantConfig.ErrorHandler
+	}
+
+	return func(c *fiber.Ctx) error {
+		if config.Skipper(c) {
+			return c.Next()
+		}
+
+		tenant, err := nethttpmw.ResolveTenant(c, config.TenantGetters)
+		if err != nil {
+			return config.ErrorHandler(c, err)
+		}
+
+		// values read from the Fiber context are only valid within the handler
+		tenant = strings.Clone(tenant)
+		c.Locals(config.ContextKey.String(), tenant)
+		c.SetUserContext(tenantctx.NewContext(c.UserContext(), tenantctx.Tenant(tenant)))
+
+		if config.SuccessHandler != nil {
+			config.SuccessHandler(c)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+module github.com/bartventer/gorm-multitenancy/middleware/fiber/v8
+
+go 1.24
+
+replace github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8 => ../nethttp
+
+replace github.com/bartventer/gorm-multitenancy/v8 => ../../
+
+require (
+	github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8 v8.8.1
+	github.com/bartventer/gorm-multitenancy/v8 v8.8.1
+	github.com/gofiber/fiber/v2 v2.52.15
+)
+
+require (
+	github.com/andybalholm/brotli v1.1.0 // indirect
+	github.com/go-viper/mapstructure/v2 v2.3.0 // indirect
+	github.com/golang-jwt/jwt/v5 v5.3.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-runewidth v0.0.16 // indirect
+	github.com/rivo/uniseg v0.2.0 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasthttp v1.51.0 // indirect
+	github.com/valyala/tcplisten v1.0.0 // indirect
+	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+	gorm.io/gorm v1.30.0 // indirect
+)
@@ -0,0 +1,71 @@
+package fibermiddleware
+
+import (
+	"fmt"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// Errors returned by [TenantFromJWT], aliases for [nethttpmw.ErrInvalidToken] and
+// [nethttpmw.ErrTenantMismatch].
+var (
+	ErrInvalidToken   = nethttpmw.ErrInvalidToken
+	ErrTenantMismatch = nethttpmw.ErrTenantMismatch
+)
+
+type (
+	// JWTKey is an alias for [nethttpmw.JWTKey].
+	JWTKey = nethttpmw.JWTKey
+
+	// JWTKeySet is an alias for [nethttpmw.JWTKeySet].
+	JWTKeySet = nethttpmw.JWTKeySet
+
+	// JWTOptions is an alias for [nethttpmw.JWTOptions].
+	JWTOptions = nethttpmw.JWTOptions
+
+	// JWTOption is an alias for [nethttpmw.JWTOption].
+	JWTOption = nethttpmw.JWTOption
+)
+
+var (
+	// NewJWTKeySet is an alias for [nethttpmw.NewJWTKeySet].
+	NewJWTKeySet = nethttpmw.NewJWTKeySet
+
+	// LoadJWKS is an alias for [nethttpmw.LoadJWKS].
+	LoadJWKS = nethttpmw.LoadJWKS
+
+	// ParseJWKS is an alias for [nethttpmw.ParseJWKS].
+	ParseJWKS = nethttpmw.ParseJWKS
+
+	// WithTenantClaim is an alias for [nethttpmw.WithTenantClaim].
+	WithTenantClaim = nethttpmw.WithTenantClaim
+
+	// WithAudience is an alias for [nethttpmw.WithAudience].
+	WithAudience = nethttpmw.WithAudience
+
+	// WithIssuer is an alias for [nethttpmw.WithIssuer].
+	WithIssuer = nethttpmw.WithIssuer
+
+	// WithLeeway is an alias for [nethttpmw.WithLeeway].
+	WithLeeway = nethttpmw.WithLeeway
+
+	// WithHostTenant is an alias for [nethttpmw.WithHostTenant]. It takes a net/http tenant
+	// getter, such as [nethttpmw.DefaultTenantFromSubdomain].
+	WithHostTenant = nethttpmw.WithHostTenant
+)
+
+// TenantFromJWT returns a tenant getter that takes the tenant from a claim of the verified bearer
+// token of the request. It converts the request to a net/http request, and calls
+// [nethttpmw.TenantFromJWT] to verify the token.
+func TenantFromJWT(keys *JWTKeySet, opts ...JWTOption) func(c *fiber.Ctx) (string, error) {
+	getter := nethttpmw.TenantFromJWT(keys, opts...)
+	return func(c *fiber.Ctx) (string, error) {
+		r, err := adaptor.ConvertRequest(c, false)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+		return getter(r)
+	}
+}
@@ -0,0 +1,27 @@
+package fibermiddleware
+
+import (
+	"strings"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DomainLookup is an alias for [nethttpmw.DomainLookup].
+type DomainLookup = nethttpmw.DomainLookup
+
+// NewDomainLookup is an alias for [nethttpmw.NewDomainLookup].
+var NewDomainLookup = nethttpmw.NewDomainLookup
+
+// NewDomainLookupFunc is an alias for [nethttpmw.NewDomainLookupFunc].
+var NewDomainLookupFunc = nethttpmw.NewDomainLookupFunc
+
+// TenantFromDomainLookup returns a tenant getter that resolves the tenant by looking up the host
+// of the request with the given [DomainLookup]. It calls [nethttpmw.DomainLookup.TenantFromHost]
+// to look up the host.
+func TenantFromDomainLookup(lookup *DomainLookup) func(c *fiber.Ctx) (string, error) {
+	return func(c *fiber.Ctx) (string, error) {
+		// the host is cached, so it must outlive the request
+		return lookup.TenantFromHost(c.UserContext(), strings.Clone(c.Hostname()))
+	}
+}
@@ -0,0 +1,55 @@
+package fibermiddleware
+
+import (
+	"strings"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExtractTenantFromPath is an alias for [nethttpmw.ExtractTenantFromPath].
+var ExtractTenantFromPath = nethttpmw.ExtractTenantFromPath
+
+// strippedTenantKey is the locals key holding the tenant removed from the path by
+// [StripTenantPathPrefix], per prefix.
+type strippedTenantKey struct {
+	prefix string
+}
+
+// TenantFromPathPrefix returns a tenant getter that extracts the tenant from the path segment
+// following the prefix, such as acme in /t/acme/books for the prefix /t. If the request passed
+// through [StripTenantPathPrefix] with the same prefix, the tenant removed from the path is
+// returned instead.
+func TenantFromPathPrefix(prefix string) func(c *fiber.Ctx) (string, error) {
+	key := strippedTenantKey{strings.Trim(prefix, "/")}
+	return func(c *fiber.Ctx) (string, error) {
+		if tenant, ok := c.Locals(key).(string); ok {
+			return tenant, nil
+		}
+		tenant, _, err := ExtractTenantFromPath(c.Path(), prefix)
+		return tenant, err
+	}
+}
+
+// StripTenantPathPrefix returns a middleware that removes the prefix and the tenant path segment
+// following it from the request path, before the routes registered after it are matched, so that
+// the routes need not be aware of the tenant. The tenant is retained in the Fiber context for
+// [TenantFromPathPrefix]. Requests whose path does not match are passed on unchanged.
+//
+//	app.Use(StripTenantPathPrefix("/t"))
+//	app.Use(WithTenant(WithTenantConfig{
+//		TenantGetters: []func(c *fiber.Ctx) (string, error){TenantFromPathPrefix("/t")},
+//	}))
+//	app.Get("/books", listBooks) // serves /t/{tenant}/books
+func StripTenantPathPrefix(prefix string) fiber.Handler {
+	key := strippedTenantKey{strings.Trim(prefix, "/")}
+	return func(c *fiber.Ctx) error {
+		tenant, rest, err := ExtractTenantFromPath(c.Path(), prefix)
+		if err != nil {
+			return c.Next()
+		}
+		c.Locals(key, strings.Clone(tenant))
+		c.Path(rest)
+		return c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package fibermiddleware
+
+import (
+	"fmt"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExtractSubdomain is an alias for [nethttpmw.ExtractSubdomain].
+var ExtractSubdomain = nethttpmw.ExtractSubdomain
+
+// SubdomainOption is an alias for [nethttpmw.SubdomainOption].
+type SubdomainOption = nethttpmw.SubdomainOption
+
+var (
+	// WithBaseDomains is an alias for [nethttpmw.WithBaseDomains].
+	WithBaseDomains = nethttpmw.WithBaseDomains
+
+	// WithPublicSuffixList is an alias for [nethttpmw.WithPublicSuffixList].
+	WithPublicSuffixList = nethttpmw.WithPublicSuffixList
+)
+
+// TenantFromSubdomain returns a tenant getter that extracts the tenant from the subdomain of the
+// request host with the given options, such as [WithBaseDomains].
+func TenantFromSubdomain(opts ...SubdomainOption) func(c *fiber.Ctx) (string, error) {
+	return func(c *fiber.Ctx) (string, error) {
+		tenant, err := ExtractSubdomain(c.Hostname(), opts...)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrTenantMissing, err)
+		}
+		return tenant, nil
+	}
+}
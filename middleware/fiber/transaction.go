@@ -0,0 +1,122 @@
+package fibermiddleware
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultReadOnly reports whether the request only reads data, based on its method (GET or HEAD).
+func DefaultReadOnly(c *fiber.Ctx) bool {
+	return c.Method() == http.MethodGet || c.Method() == http.MethodHead
+}
+
+// DefaultWithTransactionConfig is the default configuration for the WithTransaction middleware.
+// It uses the default skipper and context key, responds to errors with [ProblemErrorHandler], and
+// opens read-write transactions for all requests.
+var DefaultWithTransactionConfig = WithTransactionConfig{
+	Skipper:      DefaultSkipper,
+	ContextKey:   TenantKey,
+	ErrorHandler: ProblemErrorHandler,
+}
+
+// WithTransactionConfig represents the configuration options for the transaction middleware in
+// Fiber.
+type WithTransactionConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper func(c *fiber.Ctx) bool
+
+	// ContextKey is the key of the tenant in the Fiber context, as set by [WithTenant].
+	ContextKey fmt.Stringer
+
+	// TxOptions are the options of the transaction; optional.
+	TxOptions *sql.TxOptions
+
+	// ReadOnly reports whether the transaction of the request should be read-only, such as
+	// [DefaultReadOnly]; optional.
+	ReadOnly func(c *fiber.Ctx) bool
+
+	// ErrorHandler is a callback function that is called when the transaction cannot be opened or
+	// committed. The response has not been sent then, and the body set by the handler is
+	// discarded.
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+// WithTransaction is a middleware function that runs each request within a transaction scoped to
+// the tenant of the request, as set by [WithTenant], which must therefore run first. The
+// tenant-bound database is stored in the Fiber context, and retrieved with [DBFromContext].
+//
+// The transaction is committed if the handler returns no error and responds with a 2xx or 3xx
+// status code, and rolled back otherwise, or if the handler panics. The panic is propagated after
+// the rollback. Fiber sends the response once the handlers return, so that the client is not told
+// that the request succeeded if the commit fails: the error handler responds instead.
+//
+// Example:
+//
+//	app.Use(fibermw.WithTenant(fibermw.DefaultWithTenantConfig))
+//	app.Use(fibermw.WithTransaction(db, fibermw.WithTransactionConfig{ReadOnly: fibermw.DefaultReadOnly}))
+//
+//	app.Get("/books", func(c *fiber.Ctx) error {
+//		tx, _ := fibermw.DBFromContext(c)
+//		var books []Book
+//		if err := tx.Find(&books).Error; err != nil {
+//			return fiber.NewError(http.StatusInternalServerError, err.Error())
+//		}
+//		return c.JSON(books)
+//	})
+func WithTransaction(db *multitenancy.DB, config WithTransactionConfig) fiber.Handler {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTransactionConfig.Skipper
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTransactionConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTransactionConfig.ErrorHandler
+	}
+
+	return func(c *fiber.Ctx) error {
+		if config.Skipper(c) {
+			return c.Next()
+		}
+
+		tenant, _ := c.Locals(config.ContextKey.String()).(string)
+		readOnly := config.ReadOnly != nil && config.ReadOnly(c)
+		tx, err := nethttpmw.BeginTenantTx(c.UserContext(), db, tenant, config.TxOptions, readOnly)
+		if err != nil {
+			return config.ErrorHandler(c, err)
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				_ = tx.Rollback()
+				panic(p)
+			}
+		}()
+
+		c.Locals(DBKey.String(), tx.DB)
+		err = c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil || status < http.StatusOK || status >= http.StatusBadRequest {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.End(true); err != nil {
+			c.Response().ResetBody()
+			return config.ErrorHandler(c, err)
+		}
+		return nil
+	}
+}
+
+// DBFromContext returns the tenant-bound database stored in the Fiber context by [WithTransaction].
+func DBFromContext(c *fiber.Ctx) (*multitenancy.DB, bool) {
+	db, ok := c.Locals(DBKey.String()).(*multitenancy.DB)
+	return db, ok
+}
@@ -0,0 +1,80 @@
+package fibermiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/drivertest/fakedb"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+func TestWithTransaction(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      fiber.Handler
+		wantCommit   bool
+		wantRollback bool
+	}{
+		{name: "commit", handler: func(c *fiber.Ctx) error { return c.SendStatus(http.StatusCreated) }, wantCommit: true},
+		{name: "rollback on status", handler: func(c *fiber.Ctx) error { return c.SendStatus(http.StatusConflict) }, wantRollback: true},
+		{name: "rollback on error", handler: func(c *fiber.Ctx) error { return errors.New("oops") }, wantRollback: true},
+		{name: "rollback on panic", handler: func(c *fiber.Ctx) error { panic("boom") }, wantRollback: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _, pool := fakedb.New(t)
+			app := fiber.New()
+			app.Use(recover.New())
+			app.Use(WithTenant(DefaultWithTenantConfig))
+			app.Use(WithTransaction(db, WithTransactionConfig{}))
+			app.Post("/", func(c *fiber.Ctx) error {
+				if _, ok := DBFromContext(c); !ok {
+					t.Error("DBFromContext() ok = false, want true")
+				}
+				return tt.handler(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Host = "tenant1.example.com"
+			serve(t, app, req)
+			assertEqual(t, tt.wantCommit, pool.Committed == 1)
+			assertEqual(t, tt.wantRollback, pool.RolledBack == 1)
+		})
+	}
+}
+
+func TestWithTransaction_CommitError(t *testing.T) {
+	db, _, pool := fakedb.New(t)
+	pool.CommitErr = errors.New("serialization failure")
+	app := fiber.New()
+	app.Use(WithTenant(DefaultWithTenantConfig))
+	app.Use(WithTransaction(db, WithTransactionConfig{}))
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.Status(http.StatusCreated).SendString("created")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Host = "tenant1.example.com"
+	code, body := serve(t, app, req)
+	assertEqual(t, http.StatusInternalServerError, code)
+	if strings.Contains(body, "created") {
+		t.Errorf("body = %q, want the response of the handler discarded", body)
+	}
+}
+
+func TestWithTransaction_NoTenant(t *testing.T) {
+	db, _, _ := fakedb.New(t)
+	app := fiber.New()
+	app.Use(WithTransaction(db, WithTransactionConfig{}))
+	app.Post("/", func(c *fiber.Ctx) error {
+		t.Error("handler called")
+		return nil
+	})
+
+	code, _ := serve(t, app, httptest.NewRequest(http.MethodPost, "/", nil))
+	assertEqual(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,23 @@
+package ginmiddleware
+
+import (
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is an alias for [nethttpmw.ProblemContentType].
+const ProblemContentType = nethttpmw.ProblemContentType
+
+// Problem is an alias for [nethttpmw.Problem].
+type Problem = nethttpmw.Problem
+
+// NewProblem is an alias for [nethttpmw.NewProblem].
+var NewProblem = nethttpmw.NewProblem
+
+// ProblemErrorHandler is an error handler that aborts the request with the [Problem] of the
+// error, as application/problem+json. It is the default error handler of [WithTenant].
+func ProblemErrorHandler(c *gin.Context, err error) {
+	p := nethttpmw.NewProblem(err, c.Request.URL.Path)
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
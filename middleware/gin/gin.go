@@ -28,9 +28,9 @@ package ginmiddleware
 
 import (
 	"fmt"
-	"net/http"
 
 	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	tenantctx "github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
 	"github.com/gin-gonic/gin"
 )
 
@@ -57,10 +57,8 @@ var (
 			DefaultTenantFromSubdomain,
 			DefaultTenantFromHeader,
 		},
-		ContextKey: TenantKey,
-		ErrorHandler: func(c *gin.Context, _ error) {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": nethttpmw.ErrTenantInvalid.Error()})
-		},
+		ContextKey:   TenantKey,
+		ErrorHandler: ProblemErrorHandler,
 	}
 )
 
@@ -75,6 +73,10 @@ type WithTenantConfig struct {
 	// The functions are executed in order until a valid tenant is found.
 	TenantGetters []func(c *gin.Context) (string, error)
 
+	// TrustedProxies are the proxies trusted to forward the original host of the request, which
+	// the tenant getters then use instead of the Host of the request; optional.
+	TrustedProxies *TrustedProxies
+
 	// ContextKey is the key used to store the tenant in the context.
 	ContextKey fmt.Stringer
 
@@ -86,7 +88,9 @@ type WithTenantConfig struct {
 	SuccessHandler func(c *gin.Context)
 }
 
-// WithTenant is a middleware function that adds multi-tenancy support to a Gin application.
+// WithTenant is a middleware function that adds multi-tenancy support to a Gin application. The
+// tenant is set in the Gin context using the ContextKey, and in the context of the request with
+// [tenantctx.NewContext].
 func WithTenant(config WithTenantConfig) gin.HandlerFunc {
 	if config.Skipper == nil {
 		config.Skipper = DefaultWithTenantConfig.Skipper
@@ -110,20 +114,17 @@ func WithTenant(config WithTenantConfig) gin.HandlerFunc {
 			return
 		}
 
-		var tenant string
-		var err error
-		for _, getter := range config.TenantGetters {
-			tenant, err = getter(c)
-			if err == nil {
-				break
-			}
+		if config.TrustedProxies != nil {
+			c.Request = nethttpmw.ResolveRequestHost(c.Request, config.TrustedProxies)
 		}
+		tenant, err := nethttpmw.ResolveTenant(c, config.TenantGetters)
 		if err != nil {
 			config.ErrorHandler(c, err)
 			return
 		}
 
 		c.Set(config.ContextKey.String(), tenant)
+		c.Request = c.Request.WithContext(tenantctx.NewContext(c.Request.Context(), tenantctx.Tenant(tenant)))
 
 		if config.SuccessHandler != nil {
 			config.SuccessHandler(c)
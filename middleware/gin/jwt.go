@@ -0,0 +1,63 @@
+package ginmiddleware
+
+import (
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/gin-gonic/gin"
+)
+
+// Errors returned by [TenantFromJWT], aliases for [nethttpmw.ErrInvalidToken] and
+// [nethttpmw.ErrTenantMismatch].
+var (
+	ErrInvalidToken   = nethttpmw.ErrInvalidToken
+	ErrTenantMismatch = nethttpmw.ErrTenantMismatch
+)
+
+type (
+	// JWTKey is an alias for [nethttpmw.JWTKey].
+	JWTKey = nethttpmw.JWTKey
+
+	// JWTKeySet is an alias for [nethttpmw.JWTKeySet].
+	JWTKeySet = nethttpmw.JWTKeySet
+
+	// JWTOptions is an alias for [nethttpmw.JWTOptions].
+	JWTOptions = nethttpmw.JWTOptions
+
+	// JWTOption is an alias for [nethttpmw.JWTOption].
+	JWTOption = nethttpmw.JWTOption
+)
+
+var (
+	// NewJWTKeySet is an alias for [nethttpmw.NewJWTKeySet].
+	NewJWTKeySet = nethttpmw.NewJWTKeySet
+
+	// LoadJWKS is an alias for [nethttpmw.LoadJWKS].
+	LoadJWKS = nethttpmw.LoadJWKS
+
+	// ParseJWKS is an alias for [nethttpmw.ParseJWKS].
+	ParseJWKS = nethttpmw.ParseJWKS
+
+	// WithTenantClaim is an alias for [nethttpmw.WithTenantClaim].
+	WithTenantClaim = nethttpmw.WithTenantClaim
+
+	// WithAudience is an alias for [nethttpmw.WithAudience].
+	WithAudience = nethttpmw.WithAudience
+
+	// WithIssuer is an alias for [nethttpmw.WithIssuer].
+	WithIssuer = nethttpmw.WithIssuer
+
+	// WithLeeway is an alias for [nethttpmw.WithLeeway].
+	WithLeeway = nethttpmw.WithLeeway
+
+	// WithHostTenant is an alias for [nethttpmw.WithHostTenant]. It takes a net/http tenant
+	// getter, such as [nethttpmw.DefaultTenantFromSubdomain].
+	WithHostTenant = nethttpmw.WithHostTenant
+)
+
+// TenantFromJWT returns a tenant getter that takes the tenant from a claim of the verified bearer
+// token of the request. It calls [nethttpmw.TenantFromJWT] to verify the token.
+func TenantFromJWT(keys *JWTKeySet, opts ...JWTOption) func(c *gin.Context) (string, error) {
+	getter := nethttpmw.TenantFromJWT(keys, opts...)
+	return func(c *gin.Context) (string, error) {
+		return getter(c.Request)
+	}
+}
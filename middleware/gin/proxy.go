@@ -0,0 +1,29 @@
+package ginmiddleware
+
+import nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+
+// Headers carrying the original host of requests forwarded by proxies, aliases for
+// [nethttpmw.HeaderForwarded] and [nethttpmw.HeaderXForwardedHost].
+const (
+	HeaderForwarded      = nethttpmw.HeaderForwarded
+	HeaderXForwardedHost = nethttpmw.HeaderXForwardedHost
+)
+
+type (
+	// TrustedProxies is an alias for [nethttpmw.TrustedProxies].
+	TrustedProxies = nethttpmw.TrustedProxies
+
+	// ProxyOption is an alias for [nethttpmw.ProxyOption].
+	ProxyOption = nethttpmw.ProxyOption
+)
+
+var (
+	// NewTrustedProxies is an alias for [nethttpmw.NewTrustedProxies].
+	NewTrustedProxies = nethttpmw.NewTrustedProxies
+
+	// WithHostHeaders is an alias for [nethttpmw.WithHostHeaders].
+	WithHostHeaders = nethttpmw.WithHostHeaders
+
+	// RequestHost is an alias for [nethttpmw.RequestHost].
+	RequestHost = nethttpmw.RequestHost
+)
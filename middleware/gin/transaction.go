@@ -0,0 +1,167 @@
+package ginmiddleware
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultReadOnly reports whether the request only reads data, based on its method (GET or HEAD).
+// It calls the default [nethttpmw.DefaultReadOnly] function.
+func DefaultReadOnly(c *gin.Context) bool {
+	return nethttpmw.DefaultReadOnly(c.Request)
+}
+
+// DefaultWithTransactionConfig is the default configuration for the WithTransaction middleware.
+// It uses the default skipper and context key, responds to errors with [ProblemErrorHandler], and
+// opens read-write transactions for all requests.
+var DefaultWithTransactionConfig = WithTransactionConfig{
+	Skipper:      DefaultSkipper,
+	ContextKey:   TenantKey,
+	ErrorHandler: ProblemErrorHandler,
+}
+
+// WithTransactionConfig represents the configuration options for the transaction middleware in
+// Gin.
+type WithTransactionConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper func(c *gin.Context) bool
+
+	// ContextKey is the key of the tenant in the Gin context, as set by [WithTenant].
+	ContextKey fmt.Stringer
+
+	// TxOptions are the options of the transaction; optional.
+	TxOptions *sql.TxOptions
+
+	// ReadOnly reports whether the transaction of the request should be read-only, such as
+	// [DefaultReadOnly]; optional.
+	ReadOnly func(c *gin.Context) bool
+
+	// ErrorHandler is a callback function that is called when the transaction cannot be opened or
+	// committed. The response has not been written then, and the response of the handlers is
+	// discarded.
+	ErrorHandler func(c *gin.Context, err error)
+}
+
+// WithTransaction is a middleware function that runs each request within a transaction scoped to
+// the tenant of the request, as set by [WithTenant], which must therefore run first. The
+// tenant-bound database is stored in the Gin context, and retrieved with [DBFromContext].
+//
+// The transaction is committed if the handlers record no errors and respond with a 2xx or 3xx
+// status code, and rolled back otherwise, or if a handler panics. The panic is propagated after
+// the rollback. The transaction ends before the status line of the response is written, so that
+// the client is not told that the request succeeded if the commit fails: the response of the
+// handlers is then discarded, and the error handler responds instead.
+//
+// Example:
+//
+//	r.Use(ginmw.WithTenant(ginmw.DefaultWithTenantConfig))
+//	r.Use(ginmw.WithTransaction(db, ginmw.WithTransactionConfig{ReadOnly: ginmw.DefaultReadOnly}))
+//
+//	r.GET("/books", func(c *gin.Context) {
+//		tx, _ := ginmw.DBFromContext(c)
+//		var books []Book
+//		if err := tx.Find(&books).Error; err != nil {
+//			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+//			return
+//		}
+//		c.JSON(http.StatusOK, books)
+//	})
+func WithTransaction(db *multitenancy.DB, config WithTransactionConfig) gin.HandlerFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTransactionConfig.Skipper
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTransactionConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTransactionConfig.ErrorHandler
+	}
+
+	return func(c *gin.Context) {
+		if config.Skipper(c) {
+			c.Next()
+			return
+		}
+
+		tenant := c.GetString(config.ContextKey.String())
+		readOnly := config.ReadOnly != nil && config.ReadOnly(c)
+		tx, err := nethttpmw.BeginTenantTx(c.Request.Context(), db, tenant, config.TxOptions, readOnly)
+		if err != nil {
+			config.ErrorHandler(c, err)
+			return
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				_ = tx.Rollback()
+				panic(p)
+			}
+		}()
+
+		w := c.Writer
+		tw := &txResponseWriter{ResponseWriter: w}
+		tw.tx = nethttpmw.NewTxResponseWriter(w, tx, func(status int) bool {
+			return len(c.Errors) == 0 && status >= http.StatusOK && status < http.StatusBadRequest
+		}, func(err error) {
+			// Respond through the underlying writer; later writes of the handlers fail.
+			c.Writer = w
+			defer func() { c.Writer = tw }()
+			config.ErrorHandler(c, err)
+		})
+		c.Writer = tw
+
+		c.Set(DBKey.String(), tx.DB)
+		c.Next()
+		_ = tw.end() // the handlers may have written no response
+	}
+}
+
+// txResponseWriter ends the transaction of the request before the status line of the response is
+// written, which Gin defers until the body is written or the handlers return.
+type txResponseWriter struct {
+	gin.ResponseWriter
+	tx *nethttpmw.TxResponseWriter
+}
+
+func (w *txResponseWriter) end() error {
+	return w.tx.End(w.Status())
+}
+
+func (w *txResponseWriter) WriteHeaderNow() {
+	if w.end() == nil {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *txResponseWriter) Write(b []byte) (int, error) {
+	if err := w.end(); err != nil {
+		return 0, err
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *txResponseWriter) WriteString(s string) (int, error) {
+	if err := w.end(); err != nil {
+		return 0, err
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *txResponseWriter) Flush() {
+	if w.end() == nil {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// DBFromContext returns the tenant-bound database stored in the Gin context by [WithTransaction].
+func DBFromContext(c *gin.Context) (*multitenancy.DB, bool) {
+	v, _ := c.Get(DBKey.String())
+	db, ok := v.(*multitenancy.DB)
+	return db, ok
+}
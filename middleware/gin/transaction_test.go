@@ -0,0 +1,85 @@
+package ginmiddleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/drivertest/fakedb"
+	"github.com/gin-gonic/gin"
+)
+
+func TestWithTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name         string
+		handler      gin.HandlerFunc
+		wantCommit   bool
+		wantRollback bool
+	}{
+		{name: "commit", handler: func(c *gin.Context) { c.Status(http.StatusCreated) }, wantCommit: true},
+		{name: "rollback on status", handler: func(c *gin.Context) { c.Status(http.StatusConflict) }, wantRollback: true},
+		{name: "rollback on error", handler: func(c *gin.Context) { _ = c.Error(errors.New("oops")) }, wantRollback: true},
+		{name: "rollback on panic", handler: func(c *gin.Context) { panic("boom") }, wantRollback: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _, pool := fakedb.New(t)
+			r := gin.New()
+			r.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, _ any) {
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}))
+			r.Use(WithTenant(DefaultWithTenantConfig))
+			r.Use(WithTransaction(db, WithTransactionConfig{}))
+			r.POST("/", func(c *gin.Context) {
+				if _, ok := DBFromContext(c); !ok {
+					t.Error("DBFromContext() ok = false, want true")
+				}
+				tt.handler(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Host = "tenant1.example.com"
+			r.ServeHTTP(httptest.NewRecorder(), req)
+			assertEqual(t, tt.wantCommit, pool.Committed == 1)
+			assertEqual(t, tt.wantRollback, pool.RolledBack == 1)
+		})
+	}
+}
+
+func TestWithTransaction_CommitError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, _, pool := fakedb.New(t)
+	pool.CommitErr = errors.New("serialization failure")
+	r := gin.New()
+	r.Use(WithTenant(DefaultWithTenantConfig))
+	r.Use(WithTransaction(db, WithTransactionConfig{}))
+	r.POST("/", func(c *gin.Context) {
+		c.String(http.StatusCreated, "created")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Host = "tenant1.example.com"
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assertEqual(t, http.StatusInternalServerError, rec.Code)
+	assertEqual(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	if strings.Contains(rec.Body.String(), "created") {
+		t.Errorf("body = %q, want the response of the handler discarded", rec.Body.String())
+	}
+}
+
+func TestWithTransaction_NoTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, _, _ := fakedb.New(t)
+	r := gin.New()
+	r.Use(WithTransaction(db, WithTransactionConfig{}))
+	r.POST("/", func(c *gin.Context) { t.Error("handler called") })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assertEqual(t, http.StatusBadRequest, rec.Code)
+}
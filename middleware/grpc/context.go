@@ -0,0 +1,29 @@
+package grpcmiddleware
+
+import "context"
+
+type contextKey struct {
+	name string
+}
+
+func (c contextKey) String() string {
+	return "gorm-multitenancy/middleware/grpc/" + c.name
+}
+
+var (
+	// TenantKey is the key that holds the tenant in a request context.
+	TenantKey = &contextKey{"tenant"}
+)
+
+// ContextWithTenant returns a copy of ctx holding the tenant under [TenantKey], such as for
+// outgoing calls through the client interceptors.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, TenantKey, tenant)
+}
+
+// TenantFromContext returns the tenant held by ctx under [TenantKey], as set by the server
+// interceptors or [ContextWithTenant].
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(TenantKey).(string)
+	return tenant, ok && tenant != ""
+}
@@ -0,0 +1,18 @@
+module github.com/bartventer/gorm-multitenancy/middleware/grpc/v8
+
+go 1.24
+
+replace github.com/bartventer/gorm-multitenancy/v8 => ../../
+
+require (
+	github.com/bartventer/gorm-multitenancy/v8 v8.8.1
+	google.golang.org/grpc v1.73.0
+)
+
+require (
+	golang.org/x/net v0.38.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463 // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
@@ -0,0 +1,382 @@
+/*
+Package grpcmiddleware provides interceptors for [gRPC] servers and clients, which add
+multi-tenancy support.
+
+The server interceptors take the tenant from the metadata of incoming calls, validate it, and
+attach it to the context of the call. The client interceptors add the tenant held by the context
+to the metadata of outgoing calls, so that the tenant propagates across services.
+
+Example usage:
+
+	import (
+	    grpcmw "github.com/bartventer/gorm-multitenancy/middleware/grpc/v8"
+	    "google.golang.org/grpc"
+	)
+
+	func main() {
+	    srv := grpc.NewServer(
+	        grpc.ChainUnaryInterceptor(grpcmw.UnaryServerInterceptor(grpcmw.DefaultWithTenantConfig)),
+	        grpc.ChainStreamInterceptor(grpcmw.StreamServerInterceptor(grpcmw.DefaultWithTenantConfig)),
+	    )
+	    ...
+	}
+
+	func (s *server) ListBooks(ctx context.Context, req *pb.ListBooksRequest) (*pb.ListBooksResponse, error) {
+	    tenant, _ := grpcmw.TenantFromContext(ctx)
+	    ...
+	}
+
+Clients propagate the tenant with the client interceptors:
+
+	conn, err := grpc.NewClient(target,
+	    grpc.WithChainUnaryInterceptor(grpcmw.UnaryClientInterceptor(grpcmw.DefaultClientConfig)),
+	    grpc.WithChainStreamInterceptor(grpcmw.StreamClientInterceptor(grpcmw.DefaultClientConfig)),
+	)
+	...
+	resp, err := client.ListBooks(grpcmw.ContextWithTenant(ctx, "tenant1"), req)
+
+[gRPC]: https://grpc.io
+*/
+package grpcmiddleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/namespace"
+	tenantctx "github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMetadataKey is the default metadata key for the tenant. Metadata keys are lowercase,
+	// so it matches the X-Tenant header of HTTP gateways.
+	DefaultMetadataKey = "x-tenant"
+)
+
+var (
+	// ErrTenantInvalid represents an error when the tenant is invalid or not found.
+	ErrTenantInvalid = errors.New("invalid tenant or tenant not found")
+)
+
+// TenantError is an error resolving the tenant of a call, which maps to a gRPC status code. All
+// tenant errors wrap [ErrTenantInvalid].
+type TenantError struct {
+	code codes.Code
+	msg  string
+}
+
+// Error returns the error message.
+func (e *TenantError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns [ErrTenantInvalid].
+func (e *TenantError) Unwrap() error {
+	return ErrTenantInvalid
+}
+
+// Code returns the gRPC status code of the error.
+func (e *TenantError) Code() codes.Code {
+	return e.code
+}
+
+// Tenant errors. Tenant getters wrap them to report why the tenant of a call could not be
+// resolved, and the default error handler responds with their status codes.
+var (
+	// ErrTenantMissing is returned when the call does not specify a tenant, such as when the
+	// tenant metadata is empty (InvalidArgument).
+	ErrTenantMissing = &TenantError{code: codes.InvalidArgument, msg: "tenant missing"}
+
+	// ErrTenantNotFound is returned when the tenant of the call does not exist (NotFound).
+	ErrTenantNotFound = &TenantError{code: codes.NotFound, msg: "tenant not found"}
+)
+
+// Code returns the gRPC status code of an error returned by the tenant getters or the validation
+// of the tenant: the code of the [TenantError] it wraps, InvalidArgument if it only wraps
+// [ErrTenantInvalid], the code of the gRPC status or context error it wraps, and Internal
+// otherwise. If several tenant getters failed, a code other than InvalidArgument or NotFound
+// among their errors is returned first, so that a server fault is not reported as a client
+// error, then NotFound.
+func Code(err error) codes.Code {
+	if errs, ok := err.(getterErrors); ok {
+		code := codes.InvalidArgument
+		for _, err := range errs {
+			switch c := Code(err); c {
+			case codes.InvalidArgument:
+			case codes.NotFound:
+				code = c
+			default:
+				return c
+			}
+		}
+		return code
+	}
+	var te *TenantError
+	if errors.As(err, &te) {
+		return te.code
+	}
+	if errors.Is(err, ErrTenantInvalid) {
+		return codes.InvalidArgument
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Code()
+	}
+	return codes.Internal
+}
+
+// StatusErrorHandler is an error handler that returns a gRPC status error with the code of
+// [Code]. Client errors (InvalidArgument and NotFound) carry the message of the error; other
+// errors carry a generic message, so as not to disclose internal details. It is the default error
+// handler of the server interceptors.
+func StatusErrorHandler(ctx context.Context, err error) error {
+	switch code := Code(err); code {
+	case codes.InvalidArgument, codes.NotFound:
+		return status.Error(code, err.Error())
+	default:
+		return status.Error(code, "failed to resolve tenant")
+	}
+}
+
+// getterErrors are the errors of the tenant getters, if several of them failed.
+type getterErrors []error
+
+func (e getterErrors) Error() string {
+	return errors.Join(e...).Error()
+}
+
+func (e getterErrors) Unwrap() []error {
+	return e
+}
+
+// DefaultSkipper represents the default skipper.
+func DefaultSkipper(ctx context.Context, fullMethod string) bool {
+	return false
+}
+
+// TenantFromMetadata returns a tenant getter that extracts the tenant from the metadata of the
+// incoming call, under the given key.
+func TenantFromMetadata(key string) func(ctx context.Context) (string, error) {
+	key = strings.ToLower(key)
+	return func(ctx context.Context) (string, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(key)
+		if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+			return "", fmt.Errorf("%w: failed to get tenant from `%s` metadata, metadata is empty", ErrTenantMissing, key)
+		}
+		return strings.TrimSpace(values[0]), nil
+	}
+}
+
+// DefaultTenantFromMetadata extracts the tenant from the [DefaultMetadataKey] metadata of the
+// incoming call.
+func DefaultTenantFromMetadata(ctx context.Context) (string, error) {
+	return TenantFromMetadata(DefaultMetadataKey)(ctx)
+}
+
+var (
+	// DefaultWithTenantConfig is the default configuration for the server interceptors.
+	// It uses the default skipper, tenant getter, context key, and error handler.
+	DefaultWithTenantConfig = WithTenantConfig{
+		Skipper: DefaultSkipper,
+		TenantGetters: []func(ctx context.Context) (string, error){
+			DefaultTenantFromMetadata,
+		},
+		ContextKey:   TenantKey,
+		ErrorHandler: StatusErrorHandler,
+	}
+)
+
+// WithTenantConfig represents the configuration options for the server interceptors.
+type WithTenantConfig struct {
+	// Skipper defines a function to skip the interceptor, given the full method name of the call,
+	// such as "/grpc.health.v1.Health/Check".
+	Skipper func(ctx context.Context, fullMethod string) bool
+
+	// TenantGetters is a list of functions that retrieve the tenant from the context of the call.
+	// Each function should return the tenant as a string and an error if any.
+	// The functions are executed in order until a valid tenant is found.
+	TenantGetters []func(ctx context.Context) (string, error)
+
+	// ContextKey is the key used to store the tenant in the context.
+	ContextKey fmt.Stringer
+
+	// ErrorHandler is a callback function that is called when an error occurs during the tenant
+	// retrieval or validation process. The error joins the errors of the tenant getters if all of
+	// them failed, or wraps [ErrTenantInvalid] and the cause if the tenant failed validation. The
+	// returned error, preferably a gRPC status error, is returned to the client. The default error
+	// handler, [StatusErrorHandler], returns a status with the code of the error (see [Code]):
+	// InvalidArgument or NotFound for client errors, and Internal, or the code of the gRPC status
+	// returned by a tenant getter, such as Unavailable, for server faults.
+	ErrorHandler func(ctx context.Context, err error) error
+}
+
+func (config *WithTenantConfig) applyDefaults() {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTenantConfig.Skipper
+	}
+
+	if len(config.TenantGetters) == 0 {
+		config.TenantGetters = DefaultWithTenantConfig.TenantGetters
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTenantConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTenantConfig.ErrorHandler
+	}
+}
+
+// resolve returns the context of the call with the tenant attached. The tenant is retrieved with
+// the tenant getters, and validated with [namespace.Validate].
+func (config *WithTenantConfig) resolve(ctx context.Context) (context.Context, error) {
+	tenant, err := config.tenant(ctx)
+	if err != nil {
+		return nil, config.ErrorHandler(ctx, err)
+	}
+	ctx = context.WithValue(ctx, config.ContextKey, tenant)
+	return tenantctx.NewContext(ctx, tenantctx.Tenant(tenant)), nil
+}
+
+// tenant calls the tenant getters in order, and returns the first tenant resolved, once validated.
+// If all of the getters fail, it returns their errors joined.
+func (config *WithTenantConfig) tenant(ctx context.Context) (string, error) {
+	var errs []error
+	for _, getter := range config.TenantGetters {
+		tenant, err := getter(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := namespace.Validate(tenant); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrTenantInvalid, err)
+		}
+		return tenant, nil
+	}
+	if len(errs) == 1 {
+		return "", errs[0]
+	}
+	return "", getterErrors(errs)
+}
+
+// UnaryServerInterceptor returns a unary server interceptor that adds multi-tenancy support to a
+// gRPC server. It retrieves the tenant using the TenantGetters functions, validates it, and sets
+// it in the context of the call using the ContextKey and [tenantctx.NewContext]. If an error
+// occurs, the error returned by the ErrorHandler function is returned to the client.
+func UnaryServerInterceptor(config WithTenantConfig) grpc.UnaryServerInterceptor {
+	config.applyDefaults()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if config.Skipper(ctx, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := config.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a stream server interceptor that adds multi-tenancy support to
+// a gRPC server. See [UnaryServerInterceptor].
+func StreamServerInterceptor(config WithTenantConfig) grpc.StreamServerInterceptor {
+	config.applyDefaults()
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if config.Skipper(ss.Context(), info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := config.resolve(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream is a [grpc.ServerStream] with the context of the call replaced.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+var (
+	// DefaultClientConfig is the default configuration for the client interceptors.
+	// It uses the default metadata key and context key.
+	DefaultClientConfig = ClientConfig{
+		MetadataKey: DefaultMetadataKey,
+		ContextKey:  TenantKey,
+	}
+)
+
+// ClientConfig represents the configuration options for the client interceptors.
+type ClientConfig struct {
+	// MetadataKey is the metadata key under which the tenant is sent.
+	MetadataKey string
+
+	// ContextKey is the key of the tenant in the context of the call. If it holds no tenant, the
+	// tenant set with [tenantctx.NewContext] is sent, if any.
+	ContextKey fmt.Stringer
+}
+
+func (config *ClientConfig) applyDefaults() {
+	if config.MetadataKey == "" {
+		config.MetadataKey = DefaultClientConfig.MetadataKey
+	}
+	config.MetadataKey = strings.ToLower(config.MetadataKey)
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultClientConfig.ContextKey
+	}
+}
+
+// outgoing returns the context of the call with the tenant held by the context, if any, added to
+// the outgoing metadata, replacing any tenant already present. The tenant is read from the
+// ContextKey, falling back to the tenant set with [tenantctx.NewContext], such as by the tenant
+// middleware of the HTTP framework packages.
+func (config *ClientConfig) outgoing(ctx context.Context) context.Context {
+	tenant, _ := ctx.Value(config.ContextKey).(string)
+	if tenant == "" {
+		t, ok := tenantctx.FromContext(ctx)
+		if !ok {
+			return ctx
+		}
+		tenant = t.String()
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(config.MetadataKey, tenant)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// UnaryClientInterceptor returns a unary client interceptor that propagates the tenant held by
+// the context of the call, such as the context of an incoming call handled by the server
+// interceptors, to the metadata of the outgoing call.
+func UnaryClientInterceptor(config ClientConfig) grpc.UnaryClientInterceptor {
+	config.applyDefaults()
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(config.outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a stream client interceptor that propagates the tenant held by
+// the context of the call to the metadata of the outgoing call. See [UnaryClientInterceptor].
+func StreamClientInterceptor(config ClientConfig) grpc.StreamClientInterceptor {
+	config.applyDefaults()
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(config.outgoing(ctx), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,317 @@
+package grpcmiddleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer reports the tenant of each call through the tenants channel.
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	tenants chan string
+	// next, when set, is called by Check with the context of the call, to test propagation.
+	next grpc_health_v1.HealthClient
+}
+
+func (s *healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	tenant, _ := TenantFromContext(ctx)
+	s.tenants <- tenant
+	if s.next != nil {
+		return s.next.Check(ctx, req)
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	tenant, _ := TenantFromContext(stream.Context())
+	s.tenants <- tenant
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+}
+
+// startServer starts a server with the tenant interceptors over an in-memory connection, and
+// returns a client with the tenant interceptors connected to it.
+func startServer(t *testing.T, config WithTenantConfig, srv *healthServer) grpc_health_v1.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(config)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(config)),
+	)
+	grpc_health_v1.RegisterHealthServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(DefaultClientConfig)),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(DefaultClientConfig)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestServerInterceptors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   WithTenantConfig
+		ctx      func(ctx context.Context) context.Context
+		want     string
+		wantCode codes.Code
+		wantMsg  []string
+	}{
+		{
+			name:   "client interceptor",
+			config: WithTenantConfig{},
+			ctx:    func(ctx context.Context) context.Context { return ContextWithTenant(ctx, "tenant1") },
+			want:   "tenant1",
+		},
+		{
+			name:   "metadata",
+			config: WithTenantConfig{},
+			ctx: func(ctx context.Context) context.Context {
+				return metadata.AppendToOutgoingContext(ctx, "X-Tenant", "tenant2")
+			},
+			want: "tenant2",
+		},
+		{
+			name: "custom metadata key",
+			config: WithTenantConfig{
+				TenantGetters: []func(ctx context.Context) (string, error){
+					TenantFromMetadata("x-org"),
+					DefaultTenantFromMetadata,
+				},
+			},
+			ctx: func(ctx context.Context) context.Context {
+				return metadata.AppendToOutgoingContext(ctx, "x-org", "tenant3")
+			},
+			want: "tenant3",
+		},
+		{
+			name:     "missing tenant",
+			config:   WithTenantConfig{},
+			ctx:      func(ctx context.Context) context.Context { return ctx },
+			wantCode: codes.InvalidArgument,
+			wantMsg:  []string{"`x-tenant` metadata"},
+		},
+		{
+			name: "missing tenant, all getters fail",
+			config: WithTenantConfig{
+				TenantGetters: []func(ctx context.Context) (string, error){
+					TenantFromMetadata("x-org"),
+					DefaultTenantFromMetadata,
+				},
+			},
+			ctx:      func(ctx context.Context) context.Context { return ctx },
+			wantCode: codes.InvalidArgument,
+			wantMsg:  []string{"`x-org` metadata", "`x-tenant` metadata"},
+		},
+		{
+			name:     "invalid tenant",
+			config:   WithTenantConfig{},
+			ctx:      func(ctx context.Context) context.Context { return ContextWithTenant(ctx, "pg_catalog") },
+			wantCode: codes.InvalidArgument,
+			wantMsg:  []string{ErrTenantInvalid.Error(), "must not start with 'pg_'"},
+		},
+		{
+			name: "tenant not found",
+			config: WithTenantConfig{
+				TenantGetters: []func(ctx context.Context) (string, error){
+					func(ctx context.Context) (string, error) {
+						return "", fmt.Errorf("%w: no tenant registered for domain", ErrTenantNotFound)
+					},
+				},
+			},
+			ctx:      func(ctx context.Context) context.Context { return ctx },
+			wantCode: codes.NotFound,
+			wantMsg:  []string{"no tenant registered for domain"},
+		},
+		{
+			name: "server fault",
+			config: WithTenantConfig{
+				TenantGetters: []func(ctx context.Context) (string, error){
+					DefaultTenantFromMetadata,
+					func(ctx context.Context) (string, error) { return "", errors.New("connection refused") },
+				},
+			},
+			ctx:      func(ctx context.Context) context.Context { return ctx },
+			wantCode: codes.Internal,
+			wantMsg:  []string{"failed to resolve tenant"},
+		},
+		{
+			name: "status error",
+			config: WithTenantConfig{
+				TenantGetters: []func(ctx context.Context) (string, error){
+					func(ctx context.Context) (string, error) {
+						return "", fmt.Errorf("tenant directory: %w", status.Error(codes.Unavailable, "connection refused"))
+					},
+				},
+			},
+			ctx:      func(ctx context.Context) context.Context { return ctx },
+			wantCode: codes.Unavailable,
+		},
+		{
+			name: "custom error handler",
+			config: WithTenantConfig{
+				ErrorHandler: func(ctx context.Context, err error) error {
+					return status.Error(codes.Unauthenticated, err.Error())
+				},
+			},
+			ctx:      func(ctx context.Context) context.Context { return ctx },
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "skipper",
+			config: WithTenantConfig{
+				Skipper: func(ctx context.Context, fullMethod string) bool {
+					return fullMethod == grpc_health_v1.Health_Check_FullMethodName || fullMethod == grpc_health_v1.Health_Watch_FullMethodName
+				},
+			},
+			ctx: func(ctx context.Context) context.Context { return ctx },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &healthServer{tenants: make(chan string, 1)}
+			client := startServer(t, tt.config, srv)
+			ctx := tt.ctx(context.Background())
+
+			t.Run("unary", func(t *testing.T) {
+				_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("Check() error = %v, want code %v", err, tt.wantCode)
+				}
+				for _, msg := range tt.wantMsg {
+					if !strings.Contains(status.Convert(err).Message(), msg) {
+						t.Errorf("Check() error = %v, want message containing %q", err, msg)
+					}
+				}
+				if err == nil {
+					if got := <-srv.tenants; got != tt.want {
+						t.Errorf("tenant = %q, want %q", got, tt.want)
+					}
+				}
+			})
+
+			t.Run("stream", func(t *testing.T) {
+				stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = stream.Recv()
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("Recv() error = %v, want code %v", err, tt.wantCode)
+				}
+				if err == nil {
+					if got := <-srv.tenants; got != tt.want {
+						t.Errorf("tenant = %q, want %q", got, tt.want)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"missing", fmt.Errorf("%w: header is empty", ErrTenantMissing), codes.InvalidArgument},
+		{"not found", ErrTenantNotFound, codes.NotFound},
+		{"invalid", fmt.Errorf("%w: %w", ErrTenantInvalid, errors.New("too long")), codes.InvalidArgument},
+		{"server fault", errors.New("connection refused"), codes.Internal},
+		{"status", status.Error(codes.Unavailable, "unavailable"), codes.Unavailable},
+		{"deadline", fmt.Errorf("lookup: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"getters, not found", getterErrors{ErrTenantMissing, ErrTenantNotFound}, codes.NotFound},
+		{"getters, server fault", getterErrors{ErrTenantNotFound, errors.New("connection refused")}, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Errorf("Code() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !errors.Is(ErrTenantNotFound, ErrTenantInvalid) {
+		t.Error("ErrTenantNotFound does not wrap ErrTenantInvalid")
+	}
+}
+
+func TestClientInterceptors_Propagation(t *testing.T) {
+	backend := &healthServer{tenants: make(chan string, 1)}
+	frontend := &healthServer{tenants: make(chan string, 1), next: startServer(t, WithTenantConfig{}, backend)}
+	client := startServer(t, WithTenantConfig{}, frontend)
+
+	if _, err := client.Check(ContextWithTenant(context.Background(), "tenant1"), &grpc_health_v1.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-frontend.tenants; got != "tenant1" {
+		t.Errorf("frontend tenant = %q, want %q", got, "tenant1")
+	}
+	if got := <-backend.tenants; got != "tenant1" {
+		t.Errorf("backend tenant = %q, want %q", got, "tenant1")
+	}
+}
+
+func TestClientInterceptors_tenantContext(t *testing.T) {
+	srv := &healthServer{tenants: make(chan string, 1)}
+	client := startServer(t, WithTenantConfig{}, srv)
+
+	ctx := tenant.NewContext(context.Background(), "tenant1")
+	if _, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-srv.tenants; got != "tenant1" {
+		t.Errorf("tenant = %q, want %q", got, "tenant1")
+	}
+
+	// The tenant held by the ContextKey takes precedence.
+	if _, err := client.Check(ContextWithTenant(ctx, "tenant2"), &grpc_health_v1.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-srv.tenants; got != "tenant2" {
+		t.Errorf("tenant = %q, want %q", got, "tenant2")
+	}
+}
+
+func TestTenantFromContext(t *testing.T) {
+	if _, ok := TenantFromContext(context.Background()); ok {
+		t.Error("TenantFromContext() ok = true, want false")
+	}
+	if got, ok := TenantFromContext(ContextWithTenant(context.Background(), "tenant1")); !ok || got != "tenant1" {
+		t.Errorf("TenantFromContext() = %q, %v, want %q, true", got, ok, "tenant1")
+	}
+}
+
+func TestUnaryServerInterceptor_tenantContext(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(DefaultMetadataKey, "tenant1"))
+	var got tenant.Tenant
+	handler := func(ctx context.Context, req any) (any, error) {
+		got, _ = tenant.FromContext(ctx)
+		return nil, nil
+	}
+	if _, err := UnaryServerInterceptor(WithTenantConfig{})(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, handler); err != nil {
+		t.Fatal(err)
+	}
+	if got != "tenant1" {
+		t.Errorf("tenant.FromContext() = %q, want %q", got, "tenant1")
+	}
+}
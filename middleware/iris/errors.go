@@ -0,0 +1,32 @@
+package irismiddleware
+
+import (
+	"encoding/json"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/kataras/iris/v12"
+)
+
+// ProblemContentType is an alias for [nethttpmw.ProblemContentType].
+const ProblemContentType = nethttpmw.ProblemContentType
+
+// Problem is an alias for [nethttpmw.Problem].
+type Problem = nethttpmw.Problem
+
+// NewProblem is an alias for [nethttpmw.NewProblem].
+var NewProblem = nethttpmw.NewProblem
+
+// ProblemErrorHandler is an error handler that stops the request with the [Problem] of the error,
+// as application/problem+json. It is the default error handler of [WithTenant].
+func ProblemErrorHandler(ctx iris.Context, err error) {
+	p := nethttpmw.NewProblem(err, ctx.Path())
+	b, err := json.Marshal(p)
+	if err != nil {
+		ctx.StopWithError(p.Status, err)
+		return
+	}
+	ctx.StatusCode(p.Status)
+	ctx.Header("Content-Type", ProblemContentType)
+	_, _ = ctx.Write(b)
+	ctx.StopExecution()
+}
@@ -27,9 +27,9 @@ package irismiddleware
 
 import (
 	"fmt"
-	"net/http"
 
 	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	tenantctx "github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
 	"github.com/kataras/iris/v12"
 )
 
@@ -56,10 +56,8 @@ var (
 			DefaultTenantFromSubdomain,
 			DefaultTenantFromHeader,
 		},
-		ContextKey: TenantKey,
-		ErrorHandler: func(ctx iris.Context, _ error) {
-			ctx.StopWithJSON(http.StatusInternalServerError, iris.Map{"error": nethttpmw.ErrTenantInvalid.Error()})
-		},
+		ContextKey:   TenantKey,
+		ErrorHandler: ProblemErrorHandler,
 	}
 )
 
@@ -73,6 +71,10 @@ type WithTenantConfig struct {
 	// The functions are executed in order until a valid tenant is found.
 	TenantGetters []func(ctx iris.Context) (string, error)
 
+	// TrustedProxies are the proxies trusted to forward the original host of the request, which
+	// the tenant getters then use instead of the Host of the request; optional.
+	TrustedProxies *TrustedProxies
+
 	// ContextKey is the key used to store the tenant in the context.
 	ContextKey fmt.Stringer
 
@@ -86,6 +88,8 @@ type WithTenantConfig struct {
 
 // WithTenant returns a new tenant middleware with the provided configuration.
 // If the configuration is not provided, the [DefaultWithTenantConfig] is used.
+// The tenant is set in the Iris context values using the ContextKey, and in the context of the
+// request with [tenantctx.NewContext].
 func WithTenant(config WithTenantConfig) iris.Handler {
 	if config.Skipper == nil {
 		config.Skipper = DefaultWithTenantConfig.Skipper
@@ -109,20 +113,17 @@ func WithTenant(config WithTenantConfig) iris.Handler {
 			return
 		}
 
-		var tenant string
-		var err error
-		for _, getter := range config.TenantGetters {
-			tenant, err = getter(ctx)
-			if err == nil {
-				break
-			}
+		if config.TrustedProxies != nil {
+			ctx.ResetRequest(nethttpmw.ResolveRequestHost(ctx.Request(), config.TrustedProxies))
 		}
+		tenant, err := nethttpmw.ResolveTenant(ctx, config.TenantGetters)
 		if err != nil {
 			config.ErrorHandler(ctx, err)
 			return
 		}
 
 		ctx.Values().Set(config.ContextKey.String(), tenant)
+		ctx.ResetRequest(ctx.Request().WithContext(tenantctx.NewContext(ctx.Request().Context(), tenantctx.Tenant(tenant))))
 
 		if config.SuccessHandler != nil {
 			config.SuccessHandler(ctx)
@@ -0,0 +1,30 @@
+package irismiddleware
+
+import (
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	"github.com/kataras/iris/v12"
+)
+
+// ExtractTenantFromPath is an alias for [nethttpmw.ExtractTenantFromPath].
+var ExtractTenantFromPath = nethttpmw.ExtractTenantFromPath
+
+// StripTenantPathPrefix is an alias for [nethttpmw.StripTenantPathPrefix]. It wraps the
+// application, so that the routes need not be aware of the tenant:
+//
+//	app.Use(WithTenant(WithTenantConfig{
+//		TenantGetters: []func(ctx iris.Context) (string, error){TenantFromPathPrefix("/t")},
+//	}))
+//	app.Get("/books", listBooks) // serves /t/{tenant}/books
+//	app.WrapRouter(func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+//		StripTenantPathPrefix("/t")(router).ServeHTTP(w, r)
+//	})
+var StripTenantPathPrefix = nethttpmw.StripTenantPathPrefix
+
+// TenantFromPathPrefix returns a tenant getter that extracts the tenant from the path segment
+// following the prefix. It calls [nethttpmw.TenantFromPathPrefix] to extract the tenant.
+func TenantFromPathPrefix(prefix string) func(ctx iris.Context) (string, error) {
+	getter := nethttpmw.TenantFromPathPrefix(prefix)
+	return func(ctx iris.Context) (string, error) {
+		return getter(ctx.Request())
+	}
+}
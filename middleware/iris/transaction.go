@@ -0,0 +1,130 @@
+package irismiddleware
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	nethttpmw "github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8"
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+	"github.com/kataras/iris/v12"
+)
+
+// DefaultReadOnly reports whether the request only reads data, based on its method (GET or HEAD).
+// It calls the default [nethttpmw.DefaultReadOnly] function.
+func DefaultReadOnly(ctx iris.Context) bool {
+	return nethttpmw.DefaultReadOnly(ctx.Request())
+}
+
+// DefaultWithTransactionConfig is the default configuration for the WithTransaction middleware.
+// It uses the default skipper and context key, responds to errors with [ProblemErrorHandler], and
+// opens read-write transactions for all requests.
+var DefaultWithTransactionConfig = WithTransactionConfig{
+	Skipper:      DefaultSkipper,
+	ContextKey:   TenantKey,
+	ErrorHandler: ProblemErrorHandler,
+}
+
+// WithTransactionConfig represents the configuration options for the transaction middleware in
+// Iris.
+type WithTransactionConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper func(ctx iris.Context) bool
+
+	// ContextKey is the key of the tenant in the Iris context, as set by [WithTenant].
+	ContextKey fmt.Stringer
+
+	// TxOptions are the options of the transaction; optional.
+	TxOptions *sql.TxOptions
+
+	// ReadOnly reports whether the transaction of the request should be read-only, such as
+	// [DefaultReadOnly]; optional.
+	ReadOnly func(ctx iris.Context) bool
+
+	// ErrorHandler is a callback function that is called when the transaction cannot be opened or
+	// committed. The response has not been written then, and the response of the handlers is
+	// discarded.
+	ErrorHandler func(ctx iris.Context, err error)
+}
+
+// WithTransaction returns a middleware that runs each request within a transaction scoped to the
+// tenant of the request, as set by [WithTenant], which must therefore run first. The tenant-bound
+// database is stored in the Iris context, and retrieved with [DBFromContext].
+//
+// The transaction is committed if the handlers record no error and respond with a 2xx or 3xx
+// status code, and rolled back otherwise, or if a handler panics. The panic is propagated after
+// the rollback. The response is recorded (see iris.Context.Record) and written once the
+// transaction has ended, so that the client is not told that the request succeeded if the commit
+// fails: the response of the handlers is then discarded, and the error handler responds instead.
+// Responses flushed by the handlers are written before the transaction ends.
+//
+// Example:
+//
+//	app.Use(irismiddleware.WithTenant(irismiddleware.DefaultWithTenantConfig))
+//	app.Use(irismiddleware.WithTransaction(db, irismiddleware.WithTransactionConfig{ReadOnly: irismiddleware.DefaultReadOnly}))
+//
+//	app.Get("/books", func(ctx iris.Context) {
+//		tx, _ := irismiddleware.DBFromContext(ctx)
+//		var books []Book
+//		if err := tx.Find(&books).Error; err != nil {
+//			ctx.StopWithError(http.StatusInternalServerError, err)
+//			return
+//		}
+//		ctx.JSON(books)
+//	})
+func WithTransaction(db *multitenancy.DB, config WithTransactionConfig) iris.Handler {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTransactionConfig.Skipper
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTransactionConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTransactionConfig.ErrorHandler
+	}
+
+	return func(ctx iris.Context) {
+		if config.Skipper(ctx) {
+			ctx.Next()
+			return
+		}
+
+		tenant := ctx.Values().GetString(config.ContextKey.String())
+		readOnly := config.ReadOnly != nil && config.ReadOnly(ctx)
+		tx, err := nethttpmw.BeginTenantTx(ctx.Request().Context(), db, tenant, config.TxOptions, readOnly)
+		if err != nil {
+			config.ErrorHandler(ctx, err)
+			return
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				_ = tx.Rollback()
+				panic(p)
+			}
+		}()
+
+		ctx.Values().Set(DBKey.String(), tx.DB)
+		ctx.Record()
+		ctx.Next()
+
+		status := ctx.GetStatusCode()
+		if ctx.GetErr() != nil || status < http.StatusOK || status >= http.StatusBadRequest {
+			_ = tx.Rollback()
+			return
+		}
+		if err := tx.End(true); err != nil {
+			rec := ctx.Recorder()
+			rec.ResetBody()
+			rec.ResetHeaders()
+			config.ErrorHandler(ctx, err)
+		}
+	}
+}
+
+// DBFromContext returns the tenant-bound database stored in the Iris context by [WithTransaction].
+func DBFromContext(ctx iris.Context) (*multitenancy.DB, bool) {
+	db, ok := ctx.Values().Get(DBKey.String()).(*multitenancy.DB)
+	return db, ok
+}
@@ -0,0 +1,82 @@
+package irismiddleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/drivertest/fakedb"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestWithTransaction(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      iris.Handler
+		wantCommit   bool
+		wantRollback bool
+	}{
+		{name: "commit", handler: func(ctx iris.Context) { ctx.StatusCode(http.StatusCreated) }, wantCommit: true},
+		{name: "rollback on status", handler: func(ctx iris.Context) { ctx.StatusCode(http.StatusConflict) }, wantRollback: true},
+		{name: "rollback on error", handler: func(ctx iris.Context) { ctx.SetErr(iris.ErrNotFound) }, wantRollback: true},
+		{name: "rollback on panic", handler: func(ctx iris.Context) { panic("boom") }, wantRollback: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _, pool := fakedb.New(t)
+			app := iris.New()
+			app.Logger().SetOutput(io.Discard)
+			app.Use(func(ctx iris.Context) {
+				defer func() {
+					if p := recover(); p != nil {
+						ctx.StopWithStatus(http.StatusInternalServerError)
+					}
+				}()
+				ctx.Next()
+			})
+			app.Use(WithTenant(DefaultWithTenantConfig))
+			app.Use(WithTransaction(db, WithTransactionConfig{}))
+			app.Post("/", func(ctx iris.Context) {
+				if _, ok := DBFromContext(ctx); !ok {
+					t.Error("DBFromContext() ok = false, want true")
+				}
+				tt.handler(ctx)
+			})
+
+			httptest.New(t, app).POST("/").WithHost("tenant1.example.com").Expect()
+			if (pool.Committed == 1) != tt.wantCommit || (pool.RolledBack == 1) != tt.wantRollback {
+				t.Errorf("committed = %d, rolled back = %d, want commit %v, rollback %v", pool.Committed, pool.RolledBack, tt.wantCommit, tt.wantRollback)
+			}
+		})
+	}
+}
+
+func TestWithTransaction_CommitError(t *testing.T) {
+	db, _, pool := fakedb.New(t)
+	pool.CommitErr = errors.New("serialization failure")
+	app := iris.New()
+	app.Logger().SetOutput(io.Discard)
+	app.Use(WithTenant(DefaultWithTenantConfig))
+	app.Use(WithTransaction(db, WithTransactionConfig{}))
+	app.Post("/", func(ctx iris.Context) {
+		ctx.StatusCode(http.StatusCreated)
+		_, _ = ctx.WriteString("created")
+	})
+
+	res := httptest.New(t, app).POST("/").WithHost("tenant1.example.com").Expect()
+	res.Status(http.StatusInternalServerError)
+	res.Header("Content-Type").HasPrefix(ProblemContentType)
+	res.Body().NotContains("created")
+}
+
+func TestWithTransaction_NoTenant(t *testing.T) {
+	db, _, _ := fakedb.New(t)
+	app := iris.New()
+	app.Logger().SetOutput(io.Discard)
+	app.Use(WithTransaction(db, WithTransactionConfig{}))
+	app.Post("/", func(ctx iris.Context) { t.Error("handler called") })
+
+	httptest.New(t, app).POST("/").Expect().Status(http.StatusBadRequest)
+}
@@ -11,4 +11,7 @@ func (c contextKey) String() string {
 var (
 	// TenantKey is the key that holds the tenant in a request context.
 	TenantKey = &contextKey{"tenant"}
+
+	// DBKey is the key that holds the tenant-bound database in a request context.
+	DBKey = &contextKey{"db"}
 )
@@ -0,0 +1,185 @@
+package nethttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// TenantError is an error resolving the tenant of a request, which maps to an HTTP status code.
+// All tenant errors wrap [ErrTenantInvalid].
+type TenantError struct {
+	status int
+	msg    string
+	err    error
+}
+
+func newTenantError(status int, msg string, err error) *TenantError {
+	if err == nil {
+		err = ErrTenantInvalid
+	}
+	return &TenantError{status: status, msg: msg, err: err}
+}
+
+// Error returns the error message.
+func (e *TenantError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the error wrapped by the tenant error.
+func (e *TenantError) Unwrap() error {
+	return e.err
+}
+
+// StatusCode returns the HTTP status code of the error.
+func (e *TenantError) StatusCode() int {
+	return e.status
+}
+
+// Tenant errors. Tenant getters wrap them to report why the tenant of a request could not be
+// resolved, and the default error handlers respond with their status codes.
+var (
+	// ErrTenantMissing is returned when the request does not specify a tenant, such as when the
+	// host has no subdomain or the tenant header is empty (400 Bad Request).
+	ErrTenantMissing = newTenantError(http.StatusBadRequest, "tenant missing", nil)
+
+	// ErrTenantNotFound is returned when the tenant of the request does not exist (404 Not Found).
+	ErrTenantNotFound = newTenantError(http.StatusNotFound, "tenant not found", nil)
+
+	// ErrTenantForbidden is returned when the client is not allowed to access the tenant of the
+	// request (403 Forbidden).
+	ErrTenantForbidden = newTenantError(http.StatusForbidden, "tenant forbidden", nil)
+
+	// ErrTenantSuspended is returned when the tenant of the request exists but is suspended (423
+	// Locked).
+	ErrTenantSuspended = newTenantError(http.StatusLocked, "tenant suspended", nil)
+)
+
+// StatusCode returns the HTTP status code of an error returned by the tenant getters: the status
+// code of the [TenantError] it wraps, or 400 Bad Request if it only wraps [ErrTenantInvalid].
+// Other errors map to 500 Internal Server Error. If several tenant getters failed, the highest
+// status code among their errors is returned, and 500 if any of them failed with another error,
+// so that a server fault is not reported as a client error.
+func StatusCode(err error) int {
+	if te := tenantError(err); te != nil {
+		return te.status
+	}
+	return http.StatusInternalServerError
+}
+
+// errTenantInvalid is the [TenantError] of errors wrapping [ErrTenantInvalid] only.
+var errTenantInvalid = &TenantError{status: http.StatusBadRequest, msg: ErrTenantInvalid.Error()}
+
+// getterErrors are the errors of the tenant getters, as joined by [ResolveTenant].
+type getterErrors []error
+
+func (e getterErrors) Error() string {
+	return errors.Join(e...).Error()
+}
+
+func (e getterErrors) Unwrap() []error {
+	return e
+}
+
+// tenantError returns the [TenantError] with the highest status code in the tree of err, or nil
+// if there is none. Errors wrapped together with a tenant error, such as with fmt.Errorf("%w: %w",
+// ErrTenantMissing, err), are its causes and are ignored; whereas a getter error without a tenant
+// error is a server fault, for which nil is returned.
+func tenantError(err error) *TenantError {
+	switch x := err.(type) {
+	case *TenantError:
+		return x
+	case getterErrors:
+		var found *TenantError
+		for _, err := range x {
+			te := tenantError(err)
+			if te == nil {
+				return nil
+			}
+			if found == nil || te.status > found.status {
+				found = te
+			}
+		}
+		return found
+	case interface{ Unwrap() []error }:
+		var found *TenantError
+		for _, err := range x.Unwrap() {
+			if te := tenantError(err); te != nil && (found == nil || te.status > found.status) {
+				found = te
+			}
+		}
+		return found
+	case interface{ Unwrap() error }:
+		return tenantError(x.Unwrap())
+	}
+	if err == ErrTenantInvalid {
+		return errTenantInvalid
+	}
+	return nil
+}
+
+// ResolveTenant calls the tenant getters in order, and returns the first tenant resolved. If all
+// of them fail, it returns their errors joined. It is used by the tenant middleware of the
+// framework packages. Not intended for direct use in application code.
+func ResolveTenant[T any](c T, getters []func(T) (string, error)) (string, error) {
+	var errs []error
+	for _, getter := range getters {
+		tenant, err := getter(c)
+		if err == nil {
+			return tenant, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 1 {
+		return "", errs[0]
+	}
+	return "", getterErrors(errs)
+}
+
+// ProblemContentType is the media type of [Problem] responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is a problem details object, as defined by RFC 7807, describing why the tenant of a
+// request could not be resolved.
+type Problem struct {
+	// Type is a URI reference identifying the problem type.
+	Type string `json:"type"`
+
+	// Title is a short summary of the problem type.
+	Title string `json:"title"`
+
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+
+	// Detail is an explanation specific to this occurrence of the problem; it is omitted for
+	// server errors, so as not to disclose internal details.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance is a URI reference identifying this occurrence of the problem, such as the path of
+	// the request.
+	Instance string `json:"instance,omitempty"`
+}
+
+// NewProblem returns the problem details of an error returned by the tenant getters, with the
+// status code of [StatusCode].
+func NewProblem(err error, instance string) Problem {
+	p := Problem{Type: "about:blank", Instance: instance}
+	if te := tenantError(err); te != nil {
+		p.Status = te.status
+		p.Detail = te.msg
+	} else {
+		p.Status = http.StatusInternalServerError
+	}
+	p.Title = http.StatusText(p.Status)
+	return p
+}
+
+// ProblemErrorHandler is an error handler that responds with the [Problem] of the error, as
+// application/problem+json. It is the default error handler of [WithTenant].
+func ProblemErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	p := NewProblem(err, r.URL.Path)
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
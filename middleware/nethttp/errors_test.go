@@ -0,0 +1,90 @@
+package nethttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusCode(t *testing.T) {
+	errDB := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "missing", err: fmt.Errorf("%w: header is empty", ErrTenantMissing), want: http.StatusBadRequest},
+		{name: "not found", err: ErrTenantNotFound, want: http.StatusNotFound},
+		{name: "forbidden", err: ErrTenantForbidden, want: http.StatusForbidden},
+		{name: "suspended", err: fmt.Errorf("acme: %w", ErrTenantSuspended), want: http.StatusLocked},
+		{name: "invalid token", err: fmt.Errorf("%w: %w", ErrInvalidToken, errDB), want: http.StatusUnauthorized},
+		{name: "tenant mismatch", err: ErrTenantMismatch, want: http.StatusForbidden},
+		{name: "invalid", err: fmt.Errorf("%w: bad name", ErrTenantInvalid), want: http.StatusBadRequest},
+		{name: "other", err: errDB, want: http.StatusInternalServerError},
+		{name: "getters", err: getterErrors{ErrTenantMissing, ErrTenantNotFound}, want: http.StatusNotFound},
+		{name: "getters with fault", err: getterErrors{errDB, ErrTenantMissing}, want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusCode(tt.err); got != tt.want {
+				t.Errorf("StatusCode() = %d, want %d", got, tt.want)
+			}
+			if !errors.Is(tt.err, ErrTenantInvalid) && tt.want != http.StatusInternalServerError {
+				t.Errorf("errors.Is(%v, ErrTenantInvalid) = false, want true", tt.err)
+			}
+		})
+	}
+}
+
+func TestResolveTenant(t *testing.T) {
+	missing := func(string) (string, error) { return "", ErrTenantMissing }
+	notFound := func(string) (string, error) { return "", ErrTenantNotFound }
+	found := func(s string) (string, error) { return s, nil }
+
+	tenant, err := ResolveTenant("acme", []func(string) (string, error){missing, found, notFound})
+	if tenant != "acme" || err != nil {
+		t.Errorf("ResolveTenant() = %q, %v, want %q, nil", tenant, err, "acme")
+	}
+
+	_, err = ResolveTenant("acme", []func(string) (string, error){missing, notFound})
+	if !errors.Is(err, ErrTenantMissing) || !errors.Is(err, ErrTenantNotFound) {
+		t.Errorf("ResolveTenant() error = %v, want both getter errors", err)
+	}
+
+	_, err = ResolveTenant("acme", []func(string) (string, error){missing})
+	if err != ErrTenantMissing {
+		t.Errorf("ResolveTenant() error = %v, want %v", err, ErrTenantMissing)
+	}
+}
+
+func TestProblemErrorHandler(t *testing.T) {
+	handler := WithTenant(DefaultWithTenantConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Host = "example.com"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	want := Problem{Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest, Detail: "tenant missing", Instance: "/books"}
+	if rec.Code != want.Status {
+		t.Errorf("status = %d, want %d", rec.Code, want.Status)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ProblemContentType)
+	}
+	var got Problem
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("problem = %+v, want %+v", got, want)
+	}
+
+	p := NewProblem(errors.New("connection refused"), "")
+	if p.Status != http.StatusInternalServerError || p.Detail != "" {
+		t.Errorf("NewProblem() = %+v, want a 500 without detail", p)
+	}
+}
@@ -1,3 +1,20 @@
 module github.com/bartventer/gorm-multitenancy/middleware/nethttp/v8
 
 go 1.24
+
+replace github.com/bartventer/gorm-multitenancy/v8 => ../../
+
+require (
+	github.com/bartventer/gorm-multitenancy/v8 v8.8.1
+	github.com/golang-jwt/jwt/v5 v5.3.1
+	golang.org/x/net v0.42.0
+	golang.org/x/sync v0.16.0
+	gorm.io/gorm v1.30.0
+)
+
+require (
+	github.com/go-viper/mapstructure/v2 v2.3.0 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
@@ -0,0 +1,326 @@
+package nethttp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Errors returned by [TenantFromJWT]. ErrInvalidToken maps to 401 Unauthorized, and
+// ErrTenantMismatch wraps [ErrTenantForbidden].
+var (
+	ErrInvalidToken   = newTenantError(http.StatusUnauthorized, "invalid token", nil)
+	ErrTenantMismatch = newTenantError(http.StatusForbidden, "tenant mismatch", ErrTenantForbidden)
+)
+
+// JWTKey is a key verifying the signature of JSON Web Tokens.
+type JWTKey struct {
+	// ID is the key ID, matched against the kid header of the token, if both are set.
+	ID string
+
+	// Algorithm restricts the key to tokens signed with the algorithm, such as RS256; optional.
+	Algorithm string
+
+	// Key is the secret of type []byte for the HMAC algorithms (HS256, HS384, HS512), an
+	// *rsa.PublicKey for the RSA algorithms (RS256, RS384, RS512, PS256, PS384, PS512), or an
+	// *ecdsa.PublicKey for the ECDSA algorithms (ES256, ES384, ES512).
+	Key interface{}
+}
+
+// JWTKeySet is a set of keys verifying the signature of JSON Web Tokens. A token is accepted if
+// it is signed by any key compatible with its algorithm and key ID.
+type JWTKeySet struct {
+	keys    []JWTKey
+	methods []string // The accepted signing algorithms.
+}
+
+// NewJWTKeySet returns a [JWTKeySet] of the given keys. It returns an error if no keys are given,
+// or if a key is of an unsupported type.
+func NewJWTKeySet(keys ...JWTKey) (*JWTKeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no JWT keys given")
+	}
+	set := &JWTKeySet{keys: slices.Clone(keys)}
+	for _, key := range keys {
+		var methods []string
+		switch k := key.Key.(type) {
+		case []byte:
+			if len(k) == 0 {
+				return nil, fmt.Errorf("JWT key %q: HMAC secret is empty", key.ID)
+			}
+			methods = []string{"HS256", "HS384", "HS512"}
+		case *rsa.PublicKey:
+			methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+		case *ecdsa.PublicKey:
+			methods = []string{"ES256", "ES384", "ES512"}
+		default:
+			return nil, fmt.Errorf("JWT key %q: unsupported key type %T", key.ID, key.Key)
+		}
+		for _, method := range methods {
+			if key.Algorithm != "" && key.Algorithm != method {
+				continue
+			}
+			if !slices.Contains(set.methods, method) {
+				set.methods = append(set.methods, method)
+			}
+		}
+	}
+	return set, nil
+}
+
+// LoadJWKS reads a JSON Web Key Set (RFC 7517) from the file at the given path. See [ParseJWKS].
+func LoadJWKS(path string) (*JWTKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JSON Web Key Set (RFC 7517). RSA, EC and symmetric (oct) keys are supported;
+// keys not intended for signatures, as declared by their use parameter, are ignored.
+func ParseJWKS(data []byte) (*JWTKeySet, error) {
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	keys := make([]JWTKey, 0, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key := JWTKey{ID: jwk.Kid, Algorithm: jwk.Alg}
+		switch jwk.Kty {
+		case "RSA":
+			n, nErr := decodeBase64URL(jwk.N)
+			e, eErr := decodeBase64URL(jwk.E)
+			if err := errors.Join(nErr, eErr); err != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("JWKS key %q: invalid RSA key", jwk.Kid)
+			}
+			key.Key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch jwk.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				return nil, fmt.Errorf("JWKS key %q: unsupported curve %q", jwk.Kid, jwk.Crv)
+			}
+			x, xErr := decodeBase64URL(jwk.X)
+			y, yErr := decodeBase64URL(jwk.Y)
+			if err := errors.Join(xErr, yErr); err != nil {
+				return nil, fmt.Errorf("JWKS key %q: invalid EC key", jwk.Kid)
+			}
+			pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+			if _, err := pub.ECDH(); err != nil {
+				return nil, fmt.Errorf("JWKS key %q: invalid EC key: %w", jwk.Kid, err)
+			}
+			key.Key = pub
+		case "oct":
+			k, err := decodeBase64URL(jwk.K)
+			if err != nil {
+				return nil, fmt.Errorf("JWKS key %q: invalid symmetric key", jwk.Kid)
+			}
+			key.Key = k
+		default:
+			return nil, fmt.Errorf("JWKS key %q: unsupported key type %q", jwk.Kid, jwk.Kty)
+		}
+		keys = append(keys, key)
+	}
+	return NewJWTKeySet(keys...)
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// keyfunc returns the keys that may have signed the token.
+func (s *JWTKeySet) keyfunc(token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	kid, _ := token.Header["kid"].(string)
+	var keys jwt.VerificationKeySet
+	for _, key := range s.keys {
+		if kid != "" && key.ID != "" && kid != key.ID {
+			continue
+		}
+		if key.Algorithm != "" && key.Algorithm != alg {
+			continue
+		}
+		var ok bool
+		switch key.Key.(type) {
+		case []byte:
+			ok = strings.HasPrefix(alg, "HS")
+		case *rsa.PublicKey:
+			ok = strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+		case *ecdsa.PublicKey:
+			ok = strings.HasPrefix(alg, "ES")
+		}
+		if ok {
+			keys.Keys = append(keys.Keys, key.Key)
+		}
+	}
+	if len(keys.Keys) == 0 {
+		return nil, fmt.Errorf("no key found for algorithm %q and key ID %q", alg, kid)
+	}
+	return keys, nil
+}
+
+// JWTOptions contains the configuration for [TenantFromJWT].
+type JWTOptions struct {
+	Claim    string        // Claim holding the tenant; defaults to "tenant".
+	Audience []string      // Accepted audiences; when set, the aud claim must contain one of them.
+	Issuer   string        // Expected issuer; when set, the iss claim must match it.
+	Leeway   time.Duration // Clock skew tolerated when checking exp and nbf.
+
+	// HostTenant, when set, derives the tenant from the request, such as from its host, and the
+	// token is rejected with [ErrTenantMismatch] unless it carries the same tenant.
+	HostTenant func(r *http.Request) (string, error)
+
+	now func() time.Time
+}
+
+func (o *JWTOptions) apply(opts ...JWTOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Claim == "" {
+		o.Claim = "tenant"
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+}
+
+// JWTOption is a function that configures the [JWTOptions].
+type JWTOption func(*JWTOptions)
+
+// WithTenantClaim sets the claim holding the tenant.
+func WithTenantClaim(claim string) JWTOption {
+	return func(o *JWTOptions) {
+		o.Claim = claim
+	}
+}
+
+// WithAudience sets the accepted audiences.
+func WithAudience(audience ...string) JWTOption {
+	return func(o *JWTOptions) {
+		o.Audience = audience
+	}
+}
+
+// WithIssuer sets the expected issuer.
+func WithIssuer(issuer string) JWTOption {
+	return func(o *JWTOptions) {
+		o.Issuer = issuer
+	}
+}
+
+// WithLeeway sets the clock skew tolerated when checking the exp and nbf claims.
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(o *JWTOptions) {
+		o.Leeway = leeway
+	}
+}
+
+// WithHostTenant rejects tokens whose tenant does not match the tenant derived from the request
+// by the given getter, such as [DefaultTenantFromSubdomain]. Requests for which the getter fails
+// are rejected as well.
+func WithHostTenant(getter func(r *http.Request) (string, error)) JWTOption {
+	return func(o *JWTOptions) {
+		o.HostTenant = getter
+	}
+}
+
+// TenantFromJWT returns a tenant getter that takes the tenant from a claim of the bearer token in
+// the Authorization header of the request. The token must be signed by a key of the key set, and
+// must carry an exp claim; the exp and nbf claims are checked against the current time, and the
+// aud and iss claims against the configured audience and issuer, if any. Unlike
+// [DefaultTenantFromHeader], the tenant cannot be chosen freely by the client.
+//
+// Errors wrap [ErrInvalidToken] if the token is missing or invalid, and [ErrTenantMismatch] if
+// the tenant does not match the tenant derived from the request (see [WithHostTenant]).
+//
+// Example:
+//
+//	keys, err := nethttp.NewJWTKeySet(nethttp.JWTKey{Key: []byte(secret)})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	handler := nethttp.WithTenant(nethttp.WithTenantConfig{
+//		TenantGetters: []func(r *http.Request) (string, error){
+//			nethttp.TenantFromJWT(keys, nethttp.WithAudience("api"), nethttp.WithHostTenant(nethttp.DefaultTenantFromSubdomain)),
+//		},
+//	})(mux)
+func TenantFromJWT(keys *JWTKeySet, opts ...JWTOption) func(r *http.Request) (string, error) {
+	options := new(JWTOptions)
+	options.apply(opts...)
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(keys.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(options.Leeway),
+		jwt.WithTimeFunc(options.now),
+	}
+	if len(options.Audience) > 0 {
+		parserOpts = append(parserOpts, jwt.WithAudience(options.Audience...))
+	}
+	if options.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(options.Issuer))
+	}
+	parser := jwt.NewParser(parserOpts...)
+
+	return func(r *http.Request) (string, error) {
+		scheme, tokenString, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		tokenString = strings.TrimSpace(tokenString)
+		if !strings.EqualFold(scheme, "Bearer") || tokenString == "" {
+			return "", fmt.Errorf("%w: missing bearer token", ErrInvalidToken)
+		}
+		claims := jwt.MapClaims{}
+		if _, err := parser.ParseWithClaims(tokenString, claims, keys.keyfunc); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+		tenant, _ := claims[options.Claim].(string)
+		if tenant = strings.TrimSpace(tenant); tenant == "" {
+			return "", fmt.Errorf("%w: claim %q is missing or not a string", ErrInvalidToken, options.Claim)
+		}
+		if options.HostTenant != nil {
+			hostTenant, err := options.HostTenant(r)
+			if err != nil {
+				return "", fmt.Errorf("%w: failed to derive tenant from request: %w", ErrTenantMismatch, err)
+			}
+			if hostTenant != tenant {
+				return "", fmt.Errorf("%w: token is for tenant %q, request is for tenant %q", ErrTenantMismatch, tenant, hostTenant)
+			}
+		}
+		return tenant, nil
+	}
+}
@@ -0,0 +1,195 @@
+package nethttp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestTenantFromJWT(t *testing.T) {
+	now := time.Now()
+	secret := []byte("secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := NewJWTKeySet(
+		JWTKey{ID: "hmac", Key: secret},
+		JWTKey{ID: "rsa", Key: &rsaKey.PublicKey},
+		JWTKey{ID: "ec", Algorithm: "ES256", Key: &ecKey.PublicKey},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{"tenant": "acme", "aud": "api", "exp": now.Add(time.Hour).Unix()}
+		for k, v := range extra {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		host    string
+		opts    []JWTOption
+		want    string
+		wantErr error
+	}{
+		{name: "HMAC", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(nil)), want: "acme"},
+		{name: "RSA", header: "Bearer " + signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa", claims(nil)), want: "acme"},
+		{name: "RSA-PSS", header: "Bearer " + signToken(t, jwt.SigningMethodPS384, rsaKey, "", claims(nil)), want: "acme"},
+		{name: "ECDSA", header: "bearer " + signToken(t, jwt.SigningMethodES256, ecKey, "ec", claims(nil)), want: "acme"},
+		{name: "custom claim", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(jwt.MapClaims{"org": "globex"})), opts: []JWTOption{WithTenantClaim("org")}, want: "globex"},
+		{name: "audience", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(nil)), opts: []JWTOption{WithAudience("web", "api")}, want: "acme"},
+		{name: "leeway", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(jwt.MapClaims{"exp": now.Add(-time.Second).Unix()})), opts: []JWTOption{WithLeeway(time.Minute)}, want: "acme"},
+		{name: "host match", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(nil)), host: "acme.example.com", opts: []JWTOption{WithHostTenant(DefaultTenantFromSubdomain)}, want: "acme"},
+
+		{name: "missing header", wantErr: ErrInvalidToken},
+		{name: "basic auth", header: "Basic dXNlcjpwYXNz", wantErr: ErrInvalidToken},
+		{name: "wrong HMAC secret", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, []byte("other"), "", claims(nil)), wantErr: ErrInvalidToken},
+		{name: "unknown RSA key", header: "Bearer " + signToken(t, jwt.SigningMethodRS256, otherRSAKey, "", claims(nil)), wantErr: ErrInvalidToken},
+		{name: "kid mismatch", header: "Bearer " + signToken(t, jwt.SigningMethodRS256, rsaKey, "ec", claims(nil)), wantErr: ErrInvalidToken},
+		{name: "algorithm restricted", header: "Bearer " + signToken(t, jwt.SigningMethodES384, mustECKey(t, elliptic.P384()), "", claims(nil)), wantErr: ErrInvalidToken},
+		{name: "none algorithm", header: "Bearer " + signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", claims(nil)), wantErr: ErrInvalidToken},
+		{name: "expired", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()})), wantErr: ErrInvalidToken},
+		{name: "no expiry", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(jwt.MapClaims{"exp": nil})), wantErr: ErrInvalidToken},
+		{name: "not yet valid", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(jwt.MapClaims{"nbf": now.Add(time.Minute).Unix()})), wantErr: ErrInvalidToken},
+		{name: "wrong audience", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(nil)), opts: []JWTOption{WithAudience("admin")}, wantErr: ErrInvalidToken},
+		{name: "wrong issuer", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(jwt.MapClaims{"iss": "evil"})), opts: []JWTOption{WithIssuer("auth")}, wantErr: ErrInvalidToken},
+		{name: "missing claim", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(jwt.MapClaims{"tenant": nil})), wantErr: ErrInvalidToken},
+		{name: "non-string claim", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(jwt.MapClaims{"tenant": 42})), wantErr: ErrInvalidToken},
+		{name: "host mismatch", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(nil)), host: "globex.example.com", opts: []JWTOption{WithHostTenant(DefaultTenantFromSubdomain)}, wantErr: ErrTenantMismatch},
+		{name: "host without tenant", header: "Bearer " + signToken(t, jwt.SigningMethodHS256, secret, "", claims(nil)), host: "example.com", opts: []JWTOption{WithHostTenant(DefaultTenantFromSubdomain)}, wantErr: ErrTenantMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.host != "" {
+				r.Host = tt.host
+			}
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			got, err := TenantFromJWT(keys, tt.opts...)(r)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("TenantFromJWT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("TenantFromJWT() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustECKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestLoadJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey := mustECKey(t, elliptic.P384())
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rsa","use":"sig","alg":"RS256","n":%q,"e":%q},
+		{"kty":"EC","kid":"ec","crv":"P-384","x":%q,"y":%q},
+		{"kty":"oct","kid":"hmac","k":%q},
+		{"kty":"RSA","kid":"enc","use":"enc","n":"AQAB","e":"AQAB"}
+	]}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()),
+		b64([]byte("secret")),
+	)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(jwks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := LoadJWKS(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys.keys) != 3 {
+		t.Fatalf("len(keys) = %d, want 3", len(keys.keys))
+	}
+
+	claims := jwt.MapClaims{"tenant": "acme", "exp": time.Now().Add(time.Hour).Unix()}
+	for _, token := range []string{
+		signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa", claims),
+		signToken(t, jwt.SigningMethodES384, ecKey, "ec", claims),
+		signToken(t, jwt.SigningMethodHS512, []byte("secret"), "hmac", claims),
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		if got, err := TenantFromJWT(keys)(r); err != nil || got != "acme" {
+			t.Errorf("TenantFromJWT() = %q, %v, want %q", got, err, "acme")
+		}
+	}
+
+	// RS512 is not allowed for the RSA key.
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+signToken(t, jwt.SigningMethodRS512, rsaKey, "rsa", claims))
+	if _, err := TenantFromJWT(keys)(r); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("TenantFromJWT() error = %v, want %v", err, ErrInvalidToken)
+	}
+
+	for name, data := range map[string]string{
+		"invalid JSON":    `{`,
+		"empty":           `{"keys":[]}`,
+		"unknown type":    `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"AA"}]}`,
+		"unknown curve":   `{"keys":[{"kty":"EC","crv":"P-192","x":"AA","y":"AA"}]}`,
+		"point off curve": `{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`,
+		"invalid RSA":     `{"keys":[{"kty":"RSA","n":"!!","e":"AQAB"}]}`,
+	} {
+		if _, err := ParseJWKS([]byte(data)); err == nil {
+			t.Errorf("ParseJWKS(%s) error = nil, want error", name)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package nethttp
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+// DomainLookupOptions contains the configuration for a [DomainLookup].
+type DomainLookupOptions struct {
+	DomainColumn string        // Column holding the domain of the tenant; defaults to "domain_url".
+	SchemaColumn string        // Column holding the schema name of the tenant; defaults to "schema_name".
+	CacheTTL     time.Duration // How long hits and misses are cached; defaults to 1 minute. Negative disables caching.
+	CacheSize    int           // Maximum number of cached hosts; defaults to 10000.
+}
+
+func (o *DomainLookupOptions) apply(opts ...DomainLookupOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.DomainColumn == "" {
+		o.DomainColumn = "domain_url"
+	}
+	if o.SchemaColumn == "" {
+		o.SchemaColumn = "schema_name"
+	}
+	if o.CacheTTL == 0 {
+		o.CacheTTL = time.Minute
+	}
+	if o.CacheSize <= 0 {
+		o.CacheSize = 10000
+	}
+}
+
+// DomainLookupOption is a function that configures the [DomainLookupOptions].
+type DomainLookupOption func(*DomainLookupOptions)
+
+// WithLookupColumns sets the columns holding the domain and the schema name of the tenant, for
+// tenant models that do not embed multitenancy.TenantModel.
+func WithLookupColumns(domainColumn, schemaColumn string) DomainLookupOption {
+	return func(o *DomainLookupOptions) {
+		o.DomainColumn = domainColumn
+		o.SchemaColumn = schemaColumn
+	}
+}
+
+// WithCacheTTL sets how long hits and misses are cached. A negative TTL disables caching.
+func WithCacheTTL(ttl time.Duration) DomainLookupOption {
+	return func(o *DomainLookupOptions) {
+		o.CacheTTL = ttl
+	}
+}
+
+// WithCacheSize sets the maximum number of cached hosts. The least recently used host is evicted
+// when the cache is full.
+func WithCacheSize(size int) DomainLookupOption {
+	return func(o *DomainLookupOptions) {
+		o.CacheSize = size
+	}
+}
+
+// DomainLookup resolves the tenant of a request by looking up the request host in the table of
+// the tenant model, such as a model embedding multitenancy.TenantModel, by its domain. This maps
+// custom domains that do not match the schema name of the tenant, and rejects hosts of tenants
+// that do not exist. Hits and misses are cached in memory, bounded in time and size.
+//
+// Safe for concurrent use by multiple goroutines.
+type DomainLookup struct {
+	query   DomainQueryFunc
+	options DomainLookupOptions
+	now     func() time.Time
+
+	group singleflight.Group // Coalesces concurrent misses by host.
+
+	mu    sync.Mutex
+	lru   *list.List               // Most recently used entries first.
+	cache map[string]*list.Element // Entries by host.
+}
+
+type domainLookupEntry struct {
+	host      string
+	tenant    string // Empty for a miss.
+	expiresAt time.Time
+}
+
+// NewDomainLookup returns a [DomainLookup] querying the table of the given tenant model with db.
+// Domains are matched case-insensitively, comparing the lowercased domain column, so an index on
+// LOWER(domain column) is recommended for large tenant tables. Soft-deleted tenants are not found.
+// It returns an error if db or model is nil.
+//
+// Example:
+//
+//	lookup, err := nethttp.NewDomainLookup(db, &Tenant{}, nethttp.WithCacheTTL(5*time.Minute))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	handler := nethttp.WithTenant(nethttp.WithTenantConfig{
+//		TenantGetters: []func(r *http.Request) (string, error){lookup.TenantFromRequest},
+//	})(mux)
+func NewDomainLookup(db *gorm.DB, model interface{}, opts ...DomainLookupOption) (*DomainLookup, error) {
+	if db == nil {
+		return nil, errors.New("domain lookup: db is nil")
+	}
+	if model == nil {
+		return nil, errors.New("domain lookup: tenant model is nil")
+	}
+	l := newDomainLookup(nil, opts...)
+	l.query = func(ctx context.Context, hosts []string) (map[string]string, error) {
+		var rows []struct {
+			TenantDomain string
+			TenantSchema string
+		}
+		err := db.WithContext(ctx).Model(model).
+			Select(l.options.DomainColumn+" AS tenant_domain", l.options.SchemaColumn+" AS tenant_schema").
+			Where("LOWER("+l.options.DomainColumn+") IN ?", hosts).
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		tenants := make(map[string]string, len(rows))
+		for _, row := range rows {
+			tenants[strings.ToLower(row.TenantDomain)] = row.TenantSchema
+		}
+		return tenants, nil
+	}
+	return l, nil
+}
+
+// DomainQueryFunc returns the schema names of the tenants registered for the given hosts, keyed
+// by host. The hosts are lowercased, and so must be the returned keys. Hosts without a tenant are
+// omitted.
+type DomainQueryFunc func(ctx context.Context, hosts []string) (map[string]string, error)
+
+// NewDomainLookupFunc returns a [DomainLookup] resolving hosts with the given function, such as
+// one calling a tenant directory service, instead of querying a tenant model table. The results
+// are cached the same way. It returns an error if query is nil.
+func NewDomainLookupFunc(query DomainQueryFunc, opts ...DomainLookupOption) (*DomainLookup, error) {
+	if query == nil {
+		return nil, errors.New("domain lookup: query func is nil")
+	}
+	return newDomainLookup(query, opts...), nil
+}
+
+func newDomainLookup(query DomainQueryFunc, opts ...DomainLookupOption) *DomainLookup {
+	l := &DomainLookup{
+		query: query,
+		now:   time.Now,
+		lru:   list.New(),
+		cache: make(map[string]*list.Element),
+	}
+	l.options.apply(opts...)
+	return l
+}
+
+// TenantFromRequest returns the schema name of the tenant registered for the host of the request,
+// as returned by [RequestHost]. It has the signature of a tenant getter (see
+// [WithTenantConfig].TenantGetters).
+func (l *DomainLookup) TenantFromRequest(r *http.Request) (string, error) {
+	return l.TenantFromHost(r.Context(), RequestHost(r))
+}
+
+// TenantFromHost returns the schema name of the tenant registered for the host. The host is
+// matched case-insensitively, first with and then without its port, if any. It returns an error
+// wrapping [ErrTenantNotFound] if no tenant is registered for the host.
+func (l *DomainLookup) TenantFromHost(ctx context.Context, host string) (string, error) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return "", &wrapped{ErrTenantNotFound, host, "host is empty"}
+	}
+	if entry, ok := l.get(host); ok {
+		if entry.tenant == "" {
+			return "", &wrapped{ErrTenantNotFound, host, "no tenant registered for domain"}
+		}
+		return entry.tenant, nil
+	}
+
+	// Concurrent misses for the same host share a single query, which is not canceled with the
+	// context of the request that started it, as other requests may be waiting for it.
+	ch := l.group.DoChan(host, func() (interface{}, error) {
+		return l.lookup(context.WithoutCancel(ctx), host)
+	})
+	var res singleflight.Result
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		return "", fmt.Errorf("failed to look up tenant for host %q: %w", host, ctx.Err())
+	}
+	if res.Err != nil {
+		return "", fmt.Errorf("failed to look up tenant for host %q: %w", host, res.Err)
+	}
+	tenant := res.Val.(string)
+	if tenant == "" {
+		return "", &wrapped{ErrTenantNotFound, host, "no tenant registered for domain"}
+	}
+	return tenant, nil
+}
+
+// lookup queries the tenant registered for the host, first with and then without its port, and
+// caches the result.
+func (l *DomainLookup) lookup(ctx context.Context, host string) (string, error) {
+	hosts := []string{host}
+	if hostNoPort, _, err := net.SplitHostPort(host); err == nil {
+		hosts = append(hosts, hostNoPort)
+	}
+	tenants, err := l.query(ctx, hosts)
+	if err != nil {
+		return "", err
+	}
+	var tenant string
+	for _, h := range hosts {
+		if tenant = tenants[h]; tenant != "" {
+			break
+		}
+	}
+	l.put(host, tenant)
+	return tenant, nil
+}
+
+// Invalidate removes the given hosts from the cache, such as after the domain of a tenant has
+// changed. Without hosts, the whole cache is cleared.
+func (l *DomainLookup) Invalidate(hosts ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(hosts) == 0 {
+		l.lru.Init()
+		clear(l.cache)
+		return
+	}
+	for _, host := range hosts {
+		if elem, ok := l.cache[strings.ToLower(host)]; ok {
+			l.remove(elem)
+		}
+	}
+}
+
+func (l *DomainLookup) get(host string) (domainLookupEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	elem, ok := l.cache[host]
+	if !ok {
+		return domainLookupEntry{}, false
+	}
+	entry := elem.Value.(*domainLookupEntry)
+	if !l.now().Before(entry.expiresAt) {
+		l.remove(elem)
+		return domainLookupEntry{}, false
+	}
+	l.lru.MoveToFront(elem)
+	return *entry, true
+}
+
+func (l *DomainLookup) put(host, tenant string) {
+	if l.options.CacheTTL < 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := &domainLookupEntry{host: host, tenant: tenant, expiresAt: l.now().Add(l.options.CacheTTL)}
+	if elem, ok := l.cache[host]; ok {
+		elem.Value = entry
+		l.lru.MoveToFront(elem)
+		return
+	}
+	l.cache[host] = l.lru.PushFront(entry)
+	for l.lru.Len() > l.options.CacheSize {
+		l.remove(l.lru.Back())
+	}
+}
+
+func (l *DomainLookup) remove(elem *list.Element) {
+	l.lru.Remove(elem)
+	delete(l.cache, elem.Value.(*domainLookupEntry).host)
+}
@@ -0,0 +1,234 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+)
+
+type fakeTenants struct {
+	domains map[string]string
+	queries int
+	err     error
+}
+
+func (f *fakeTenants) query(_ context.Context, hosts []string) (map[string]string, error) {
+	f.queries++
+	if f.err != nil {
+		return nil, f.err
+	}
+	tenants := make(map[string]string)
+	for _, host := range hosts {
+		if tenant, ok := f.domains[host]; ok {
+			tenants[host] = tenant
+		}
+	}
+	return tenants, nil
+}
+
+func mustNewDomainLookupFunc(t *testing.T, query DomainQueryFunc, opts ...DomainLookupOption) *DomainLookup {
+	t.Helper()
+	lookup, err := NewDomainLookupFunc(query, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lookup
+}
+
+func TestNewDomainLookupFunc_nilQuery(t *testing.T) {
+	if _, err := NewDomainLookupFunc(nil); err == nil {
+		t.Error("NewDomainLookupFunc(nil) error = nil, want an error")
+	}
+}
+
+func TestDomainLookup_TenantFromHost(t *testing.T) {
+	ctx := context.Background()
+	tenants := &fakeTenants{domains: map[string]string{
+		"tenant1.example.com": "tenant1",
+		"shop.acme.io":        "acme",
+		"dev.local:8080":      "dev_port",
+	}}
+	lookup := mustNewDomainLookupFunc(t, tenants.query)
+
+	tests := []struct {
+		host    string
+		want    string
+		wantErr error
+	}{
+		{host: "tenant1.example.com", want: "tenant1"},
+		{host: "SHOP.Acme.io", want: "acme"},
+		{host: "shop.acme.io:443", want: "acme"},
+		{host: "dev.local:8080", want: "dev_port"},
+		{host: "unknown.example.com", wantErr: ErrTenantNotFound},
+		{host: "", wantErr: ErrTenantNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			got, err := lookup.TenantFromHost(ctx, tt.host)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TenantFromHost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("TenantFromHost() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("request", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://shop.acme.io/", nil)
+		got, err := lookup.TenantFromRequest(r)
+		if err != nil || got != "acme" {
+			t.Errorf("TenantFromRequest() = %q, %v, want %q", got, err, "acme")
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		wantErr := errors.New("connection refused")
+		lookup := mustNewDomainLookupFunc(t, (&fakeTenants{err: wantErr}).query)
+		_, err := lookup.TenantFromHost(ctx, "tenant1.example.com")
+		if !errors.Is(err, wantErr) || errors.Is(err, ErrTenantNotFound) {
+			t.Errorf("TenantFromHost() error = %v, want %v", err, wantErr)
+		}
+	})
+}
+
+func TestDomainLookup_Cache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	tenants := &fakeTenants{domains: map[string]string{"tenant1.example.com": "tenant1"}}
+	lookup := mustNewDomainLookupFunc(t, tenants.query, WithCacheTTL(time.Minute), WithCacheSize(2))
+	lookup.now = func() time.Time { return now }
+
+	mustLookup := func(host string) {
+		t.Helper()
+		_, _ = lookup.TenantFromHost(ctx, host)
+	}
+	wantQueries := func(want int) {
+		t.Helper()
+		if tenants.queries != want {
+			t.Fatalf("queries = %d, want %d", tenants.queries, want)
+		}
+	}
+
+	// Hits and misses are cached.
+	mustLookup("tenant1.example.com")
+	mustLookup("tenant1.example.com")
+	mustLookup("unknown.example.com")
+	mustLookup("unknown.example.com")
+	wantQueries(2)
+
+	// Entries expire after the TTL.
+	now = now.Add(time.Minute)
+	mustLookup("tenant1.example.com")
+	wantQueries(3)
+
+	// The least recently used entry is evicted when the cache is full.
+	mustLookup("other.example.com")
+	mustLookup("unknown.example.com")
+	wantQueries(5)
+	mustLookup("other.example.com")
+	wantQueries(5)
+	mustLookup("tenant1.example.com")
+	wantQueries(6)
+
+	lookup.Invalidate("Tenant1.example.com")
+	mustLookup("tenant1.example.com")
+	wantQueries(7)
+
+	lookup.Invalidate()
+	mustLookup("tenant1.example.com")
+	wantQueries(8)
+}
+
+func TestDomainLookup_CoalescesMisses(t *testing.T) {
+	var queries atomic.Int32
+	release := make(chan struct{})
+	lookup := mustNewDomainLookupFunc(t, func(_ context.Context, hosts []string) (map[string]string, error) {
+		queries.Add(1)
+		<-release
+		return map[string]string{"tenant1.example.com": "tenant1"}, nil
+	})
+
+	const n = 8
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = lookup.TenantFromHost(context.Background(), "tenant1.example.com")
+		}()
+	}
+	// Wait for the first query to start before releasing it.
+	for queries.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := queries.Load(); got != 1 {
+		t.Errorf("queries = %d, want 1", got)
+	}
+	for _, got := range results {
+		if got != "tenant1" {
+			t.Errorf("TenantFromHost() = %q, want %q", got, "tenant1")
+		}
+	}
+}
+
+func TestDomainLookup_NoCache(t *testing.T) {
+	tenants := &fakeTenants{domains: map[string]string{"tenant1.example.com": "tenant1"}}
+	lookup := mustNewDomainLookupFunc(t, tenants.query, WithCacheTTL(-1))
+	for range 2 {
+		_, _ = lookup.TenantFromHost(context.Background(), "tenant1.example.com")
+	}
+	if tenants.queries != 2 {
+		t.Errorf("queries = %d, want 2", tenants.queries)
+	}
+}
+
+type lookupTenant struct {
+	ID         uint
+	DomainURL  string
+	SchemaName string
+}
+
+func (lookupTenant) TableName() string { return "public.tenants" }
+
+func TestNewDomainLookup(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sql string
+	_ = db.Callback().Row().After("gorm:row").Register("capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	})
+
+	lookup, err := NewDomainLookup(db, &lookupTenant{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = lookup.TenantFromHost(context.Background(), "Tenant1.example.com:8080")
+	want := "SELECT domain_url AS tenant_domain,schema_name AS tenant_schema FROM `public`.`tenants` WHERE LOWER(domain_url) IN (?,?)"
+	if !strings.EqualFold(sql, want) {
+		t.Errorf("query = %q, want %q", sql, want)
+	}
+
+	if _, err := NewDomainLookup(nil, &lookupTenant{}); err == nil {
+		t.Error("NewDomainLookup(nil, model) error = nil, want an error")
+	}
+	if _, err := NewDomainLookup(db, nil); err == nil {
+		t.Error("NewDomainLookup(db, nil) error = nil, want an error")
+	}
+}
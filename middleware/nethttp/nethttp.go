@@ -23,6 +23,84 @@ Example usage:
 	    http.ListenAndServe(":8080", handler)
 	}
 
+# Resolving Tenants by Domain
+
+[DefaultTenantFromSubdomain] uses the first label of the request host as the tenant. For hosts
+under multi-label public suffixes, such as acme.example.co.uk, or deeper hosts, such as
+api.acme.example.com, use [TenantFromSubdomain] with [WithPublicSuffixList] or [WithBaseDomains],
+which take the label immediately left of the registrable or base domain.
+
+Neither checks that the tenant exists. To serve tenants on custom domains, or to reject hosts of
+unknown tenants, use a [DomainLookup], which looks up the request host in the tenants table by
+its domain and caches the result:
+
+	lookup, err := nethttpmw.NewDomainLookup(db, &Tenant{})
+	if err != nil {
+	    log.Fatal(err)
+	}
+	handler := nethttpmw.WithTenant(nethttpmw.WithTenantConfig{
+	    TenantGetters: []func(r *http.Request) (string, error){lookup.TenantFromRequest},
+	})(mux)
+
+Domains are matched case-insensitively. Concurrent lookups of the same uncached host share a
+single query. Hosts without a tenant fail with an error wrapping [ErrTenantNotFound].
+
+# Resolving Tenants behind Proxies
+
+Behind a load balancer or another reverse proxy, the Host of the request may be the internal name
+of the service rather than the host requested by the client. Set the TrustedProxies of
+[WithTenantConfig] to take the host from the [HeaderForwarded] or [HeaderXForwardedHost] header
+instead, for requests whose remote address is a trusted proxy only:
+
+	proxies, err := nethttpmw.NewTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+	    log.Fatal(err)
+	}
+	handler := nethttpmw.WithTenant(nethttpmw.WithTenantConfig{TrustedProxies: proxies})(mux)
+
+The tenant getters of this package read the host with [RequestHost].
+
+# Errors
+
+The tenant getters return errors wrapping a [TenantError], which tells why the tenant could not
+be resolved: [ErrTenantMissing] when the request does not specify a tenant, [ErrTenantNotFound]
+when the tenant does not exist, [ErrTenantForbidden] when the client may not access it, and
+[ErrTenantSuspended] when it is suspended. The default error handler, [ProblemErrorHandler],
+responds with the status code of the error, as reported by [StatusCode], and an RFC 7807
+application/problem+json body, such as:
+
+	{"type":"about:blank","title":"Not Found","status":404,"detail":"tenant not found","instance":"/books"}
+
+Other errors are reported as 500 Internal Server Error, without details.
+
+# Tenant Context
+
+Besides the ContextKey, [WithTenant] stores the tenant in the request context with
+[tenantctx.NewContext], so that code shared across routers, such as a service layer, can
+retrieve it with tenant.FromContext, without depending on this package:
+
+	t, ok := tenant.FromContext(r.Context())
+
+# Resolving Tenants by Path
+
+For tenants routed by path, such as /t/{tenant}/books, use [TenantFromPathPrefix]. Wrap the
+handler with [StripTenantPathPrefix] to remove the prefix and the tenant from the path before
+routing, so that the routes need not be aware of the tenant.
+
+# Resolving Tenants from Tokens
+
+[DefaultTenantFromHeader] trusts the tenant sent by the client. To take the tenant from a signed
+bearer token instead, use [TenantFromJWT] with a [JWTKeySet] of HMAC secrets, RSA or ECDSA public
+keys, or a JSON Web Key Set loaded with [LoadJWKS]. Use [WithHostTenant] to also reject tokens
+issued for another tenant than the one derived from the host.
+
+# Request Transactions
+
+[WithTransaction] runs each request within a transaction scoped to its tenant, so that handlers
+need not call multitenancy.DB.UseTenant themselves. The transaction is committed when the
+handler succeeds, and rolled back when it fails or panics. Handlers retrieve the tenant-bound
+database with [DBFromContext].
+
 [net/http]: https://golang.org/pkg/net/http/
 */
 package nethttp
@@ -33,6 +111,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	tenantctx "github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
 )
 
 const (
@@ -52,7 +132,7 @@ func DefaultSkipper(r *http.Request) bool {
 
 // DefaultTenantFromSubdomain extracts the tenant from the subdomain in the HTTP request.
 func DefaultTenantFromSubdomain(r *http.Request) (string, error) {
-	return ExtractSubdomain(r.Host)
+	return TenantFromSubdomain()(r)
 }
 
 // DefaultTenantFromHeader extracts the tenant from the [XTenantHeader] header in the HTTP request.
@@ -61,7 +141,7 @@ func DefaultTenantFromHeader(r *http.Request) (string, error) {
 	tenant := r.Header.Get(XTenantHeader)
 	tenant = strings.TrimSpace(tenant)
 	if tenant == "" {
-		return "", fmt.Errorf("%w: failed to get tenant from `%s` header, header is empty", ErrTenantInvalid, XTenantHeader)
+		return "", fmt.Errorf("%w: failed to get tenant from `%s` header, header is empty", ErrTenantMissing, XTenantHeader)
 	}
 	return tenant, nil
 }
@@ -75,10 +155,8 @@ var (
 			DefaultTenantFromSubdomain,
 			DefaultTenantFromHeader,
 		},
-		ContextKey: TenantKey,
-		ErrorHandler: func(w http.ResponseWriter, r *http.Request, _ error) {
-			http.Error(w, ErrTenantInvalid.Error(), http.StatusInternalServerError)
-		},
+		ContextKey:   TenantKey,
+		ErrorHandler: ProblemErrorHandler,
 	}
 )
 
@@ -92,6 +170,10 @@ type WithTenantConfig struct {
 	// The functions are executed in order until a valid tenant is found.
 	TenantGetters []func(r *http.Request) (string, error)
 
+	// TrustedProxies are the proxies trusted to forward the original host of the request, which
+	// the tenant getters then use instead of the Host of the request; optional.
+	TrustedProxies *TrustedProxies
+
 	// ContextKey is the key used to store the tenant in the context.
 	ContextKey fmt.Stringer
 
@@ -108,8 +190,9 @@ type WithTenantConfig struct {
 // The WithTenantConfig struct allows customization of the middleware behavior.
 // The middleware checks if the request should be skipped based on the Skipper function.
 // It retrieves the tenant information using the TenantGetters functions.
-// If an error occurs while retrieving the tenant, the ErrorHandler function is called.
-// The retrieved tenant is then set in the request context using the ContextKey.
+// If all of them fail, the ErrorHandler function is called with their errors joined.
+// The retrieved tenant is then set in the request context using the ContextKey, and with
+// [tenantctx.NewContext].
 // Finally, the SuccessHandler function is called if provided, and the next handler is invoked.
 func WithTenant(config WithTenantConfig) func(http.Handler) http.Handler {
 	if config.Skipper == nil {
@@ -134,22 +217,15 @@ func WithTenant(config WithTenantConfig) func(http.Handler) http.Handler {
 				next.ServeHTTP(w, r)
 				return
 			}
-			var (
-				tenant string
-				err    error
-			)
-			for _, getter := range config.TenantGetters {
-				tenant, err = getter(r)
-				if err == nil {
-					break
-				}
-			}
+			r = ResolveRequestHost(r, config.TrustedProxies)
+			tenant, err := ResolveTenant(r, config.TenantGetters)
 			if err != nil {
 				config.ErrorHandler(w, r, err)
 				return
 			}
 			// set tenant in request context
 			ctx := context.WithValue(r.Context(), config.ContextKey, tenant)
+			ctx = tenantctx.NewContext(ctx, tenantctx.Tenant(tenant))
 			r = r.WithContext(ctx)
 
 			// call success handler
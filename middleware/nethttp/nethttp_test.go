@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/tenant"
 )
 
 func TestWithTenant(t *testing.T) {
@@ -291,3 +293,18 @@ func TestDefaultTenantFromHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestWithTenant_tenantContext(t *testing.T) {
+	handler := WithTenant(DefaultWithTenantConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ := tenant.FromContext(r.Context())
+		fmt.Fprint(w, got)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "acme.example.com"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "acme" {
+		t.Errorf("response = %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusOK, "acme")
+	}
+}
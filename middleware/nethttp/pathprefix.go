@@ -0,0 +1,110 @@
+package nethttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// strippedTenantKey is the context key holding the tenant removed from the path by
+// [StripTenantPathPrefix], per normalized prefix.
+type strippedTenantKey struct {
+	prefix string
+}
+
+// normalizePathPrefix returns the prefix with a leading and without a trailing slash, or the
+// empty string for the root.
+func normalizePathPrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// cutTenantPath splits a path of the form {prefix}/{tenant}[/{rest}] into the tenant and
+// /{rest}. The prefix must be normalized.
+func cutTenantPath(prefix, path string) (tenant, rest string, ok bool) {
+	after, found := strings.CutPrefix(path, prefix+"/")
+	if !found {
+		return "", "", false
+	}
+	tenant, rest, _ = strings.Cut(after, "/")
+	if tenant == "" {
+		return "", "", false
+	}
+	return tenant, "/" + rest, true
+}
+
+// ExtractTenantFromPath extracts the tenant from the path segment following the prefix, and
+// returns the remainder of the path. An empty prefix takes the tenant from the first path segment.
+//
+// The path is expected to be in the following format:
+//
+//	{prefix}/{tenant}[/{rest}]
+func ExtractTenantFromPath(path, prefix string) (tenant, rest string, err error) {
+	tenant, rest, ok := cutTenantPath(normalizePathPrefix(prefix), path)
+	if !ok {
+		return "", "", fmt.Errorf("%w: failed to get tenant from path %q, expected %q", ErrTenantMissing, path, normalizePathPrefix(prefix)+"/{tenant}")
+	}
+	return tenant, rest, nil
+}
+
+// TenantFromPathPrefix returns a tenant getter that extracts the tenant from the path segment
+// following the prefix, such as acme in /t/acme/books for the prefix /t. If the request passed
+// through [StripTenantPathPrefix] with the same prefix, the tenant removed from the path is
+// returned instead.
+func TenantFromPathPrefix(prefix string) func(r *http.Request) (string, error) {
+	key := strippedTenantKey{normalizePathPrefix(prefix)}
+	return func(r *http.Request) (string, error) {
+		if tenant, ok := r.Context().Value(key).(string); ok {
+			return tenant, nil
+		}
+		tenant, _, err := ExtractTenantFromPath(r.URL.Path, prefix)
+		return tenant, err
+	}
+}
+
+// StripTenantPathPrefix returns a middleware that removes the prefix and the tenant path segment
+// following it from the request path, before calling the next handler, so that the routes
+// downstream need not be aware of the tenant. The tenant is retained in the request context for
+// [TenantFromPathPrefix]. Requests whose path does not match are passed on unchanged.
+//
+// To strip the prefix before the router of a web framework selects a route, wrap the router
+// itself:
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("/books", listBooks) // serves /t/{tenant}/books
+//	handler := nethttp.WithTenant(nethttp.WithTenantConfig{
+//		TenantGetters: []func(r *http.Request) (string, error){nethttp.TenantFromPathPrefix("/t")},
+//	})(mux)
+//	http.ListenAndServe(":8080", nethttp.StripTenantPathPrefix("/t")(handler))
+func StripTenantPathPrefix(prefix string) func(http.Handler) http.Handler {
+	prefix = normalizePathPrefix(prefix)
+	key := strippedTenantKey{prefix}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant, rest, ok := cutTenantPath(prefix, r.URL.Path)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			r2 := r.WithContext(context.WithValue(r.Context(), key, tenant))
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = rest
+			if r.URL.RawPath != "" {
+				_, rawRest, rawOK := cutTenantPath(prefix, r.URL.RawPath)
+				if rawOK {
+					r2.URL.RawPath = rawRest
+				} else {
+					r2.URL.RawPath = ""
+				}
+			}
+			r2.RequestURI = r2.URL.RequestURI()
+			next.ServeHTTP(w, r2)
+		})
+	}
+}
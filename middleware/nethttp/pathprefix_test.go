@@ -0,0 +1,85 @@
+package nethttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtractTenantFromPath(t *testing.T) {
+	tests := []struct {
+		path, prefix string
+		wantTenant   string
+		wantRest     string
+		wantErr      bool
+	}{
+		{path: "/t/acme/books", prefix: "/t", wantTenant: "acme", wantRest: "/books"},
+		{path: "/t/acme/books/1", prefix: "t/", wantTenant: "acme", wantRest: "/books/1"},
+		{path: "/t/acme", prefix: "/t", wantTenant: "acme", wantRest: "/"},
+		{path: "/t/acme/", prefix: "/t", wantTenant: "acme", wantRest: "/"},
+		{path: "/api/v1/acme/books", prefix: "/api/v1", wantTenant: "acme", wantRest: "/books"},
+		{path: "/acme/books", prefix: "", wantTenant: "acme", wantRest: "/books"},
+		{path: "/acme/books", prefix: "/", wantTenant: "acme", wantRest: "/books"},
+		{path: "/t/", prefix: "/t", wantErr: true},
+		{path: "/t", prefix: "/t", wantErr: true},
+		{path: "/tenants/acme", prefix: "/t", wantErr: true},
+		{path: "/books", prefix: "/t", wantErr: true},
+		{path: "/", prefix: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix+" "+tt.path, func(t *testing.T) {
+			tenant, rest, err := ExtractTenantFromPath(tt.path, tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtractTenantFromPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrTenantInvalid) {
+				t.Errorf("ExtractTenantFromPath() error = %v, want %v", err, ErrTenantInvalid)
+			}
+			if tenant != tt.wantTenant || rest != tt.wantRest {
+				t.Errorf("ExtractTenantFromPath() = %q, %q, want %q, %q", tenant, rest, tt.wantTenant, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestStripTenantPathPrefix(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/books/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tenant, _ := r.Context().Value(TenantKey).(string)
+		_, _ = w.Write([]byte(tenant + " " + r.PathValue("id") + " " + r.URL.RawPath + " " + r.RequestURI))
+	})
+	handler := StripTenantPathPrefix("/t")(WithTenant(WithTenantConfig{
+		TenantGetters: []func(r *http.Request) (string, error){TenantFromPathPrefix("/t")},
+	})(mux))
+
+	tests := []struct {
+		target   string
+		wantCode int
+		wantBody string
+	}{
+		{target: "/t/acme/books/1?q=x", wantCode: http.StatusOK, wantBody: "acme 1  /books/1?q=x"},
+		{target: "/t/acme/books/a%2Fb", wantCode: http.StatusOK, wantBody: "acme a/b /books/a%2Fb /books/a%2Fb"},
+		{target: "/books/1", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+
+	t.Run("without stripping", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/t/acme/books/1", nil)
+		tenant, err := TenantFromPathPrefix("/t")(req)
+		if err != nil || tenant != "acme" {
+			t.Errorf("TenantFromPathPrefix() = %q, %v, want %q", tenant, err, "acme")
+		}
+	})
+}
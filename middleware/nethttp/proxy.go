@@ -0,0 +1,211 @@
+package nethttp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Headers carrying the original host of requests forwarded by proxies.
+const (
+	// HeaderForwarded is the RFC 7239 Forwarded header, whose host parameter is the original host.
+	HeaderForwarded = "Forwarded"
+
+	// HeaderXForwardedHost is the de facto standard X-Forwarded-Host header.
+	HeaderXForwardedHost = "X-Forwarded-Host"
+)
+
+// ProxyOptions contains the configuration for [TrustedProxies].
+type ProxyOptions struct {
+	// HostHeaders are the headers the original host is read from, in order of precedence:
+	// [HeaderForwarded] and/or [HeaderXForwardedHost]. Defaults to both, in that order.
+	HostHeaders []string
+}
+
+func (o *ProxyOptions) apply(opts ...ProxyOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// ProxyOption is a function that configures the [ProxyOptions].
+type ProxyOption func(*ProxyOptions)
+
+// WithHostHeaders sets the headers the original host is read from, in order of precedence.
+func WithHostHeaders(headers ...string) ProxyOption {
+	return func(o *ProxyOptions) {
+		o.HostHeaders = headers
+	}
+}
+
+// TrustedProxies resolves the original host of requests forwarded by trusted proxies, such as
+// load balancers, from the [HeaderForwarded] or [HeaderXForwardedHost] header. The headers are
+// only read from requests whose remote address is a trusted proxy, as they are otherwise
+// controlled by the client. The trusted proxies must overwrite or append to these headers,
+// rather than pass on those sent by the client unchanged.
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+	headers  []string
+}
+
+// NewTrustedProxies returns the [TrustedProxies] with the given CIDRs, such as 10.0.0.0/8, or IP
+// addresses. It returns an error if any of them is invalid.
+func NewTrustedProxies(cidrs []string, opts ...ProxyOption) (*TrustedProxies, error) {
+	options := &ProxyOptions{HostHeaders: []string{HeaderForwarded, HeaderXForwardedHost}}
+	options.apply(opts...)
+
+	p := &TrustedProxies{headers: options.HostHeaders}
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(cidr)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		p.prefixes = append(p.prefixes, prefix.Masked())
+	}
+	return p, nil
+}
+
+// IsTrusted reports whether the address, such as the RemoteAddr of a request, with or without
+// port, is a trusted proxy.
+func (p *TrustedProxies) IsTrusted(addr string) bool {
+	ip, ok := parseNode(addr)
+	if !ok {
+		return false
+	}
+	for _, prefix := range p.prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Host returns the original host of the request if it was forwarded by a trusted proxy, and the
+// Host of the request otherwise. A nil TrustedProxies trusts no proxies.
+func (p *TrustedProxies) Host(r *http.Request) string {
+	if p == nil || !p.IsTrusted(r.RemoteAddr) {
+		return r.Host
+	}
+	for _, header := range p.headers {
+		var host string
+		switch http.CanonicalHeaderKey(header) {
+		case HeaderForwarded:
+			host = p.forwardedHost(r.Header.Values(HeaderForwarded))
+		case HeaderXForwardedHost:
+			// the rightmost value was set by the nearest proxy, whereas those before it may have
+			// been sent by the client
+			values := r.Header.Values(HeaderXForwardedHost)
+			if len(values) > 0 {
+				last := values[len(values)-1]
+				host = strings.TrimSpace(last[strings.LastIndex(last, ",")+1:])
+			}
+		}
+		if isValidHost(host) {
+			return host
+		}
+	}
+	return r.Host
+}
+
+// forwardedHost returns the host parameter of the Forwarded header element added by the first
+// trusted proxy of the chain, found by walking the elements from the nearest proxy for as long as
+// their for parameter is a trusted proxy too.
+func (p *TrustedProxies) forwardedHost(values []string) string {
+	elements := parseForwarded(strings.Join(values, ","))
+	for i := len(elements) - 1; i >= 0; i-- {
+		if i == 0 || !p.IsTrusted(elements[i]["for"]) {
+			return elements[i]["host"]
+		}
+	}
+	return ""
+}
+
+// parseForwarded parses the elements of an RFC 7239 Forwarded header into their parameters,
+// keyed by lower-case name, with quoted values unquoted.
+func parseForwarded(header string) []map[string]string {
+	var (
+		elements []map[string]string
+		element  = map[string]string{}
+		pair     strings.Builder
+		quoted   bool
+		escaped  bool
+	)
+	flushPair := func() {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair.String()), "=")
+		if ok {
+			element[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+		}
+		pair.Reset()
+	}
+	for _, c := range header {
+		switch {
+		case escaped:
+			pair.WriteRune(c)
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == ';':
+			flushPair()
+		case !quoted && c == ',':
+			flushPair()
+			elements = append(elements, element)
+			element = map[string]string{}
+		default:
+			pair.WriteRune(c)
+		}
+	}
+	flushPair()
+	return append(elements, element)
+}
+
+// parseNode parses an IP address with an optional port, as in the RemoteAddr of a request or a
+// node of a Forwarded header, such as 192.0.2.1, 192.0.2.1:8080, [2001:db8::1] or
+// [2001:db8::1]:8080.
+func parseNode(node string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		node = host
+	}
+	ip, err := netip.ParseAddr(strings.Trim(node, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+// isValidHost reports whether the host, as read from a header, is a plausible host[:port].
+func isValidHost(host string) bool {
+	return host != "" && !strings.ContainsAny(host, " \t/\\?#@,;\"")
+}
+
+// requestHostKey is the context key holding the host resolved by [ResolveRequestHost].
+type requestHostKey struct{}
+
+// ResolveRequestHost returns the request with its original host, as resolved by the proxies,
+// stored in its context for [RequestHost], or the request unchanged if proxies is nil. It is used
+// by the tenant middleware of the framework packages. Not intended for direct use in application
+// code.
+func ResolveRequestHost(r *http.Request, proxies *TrustedProxies) *http.Request {
+	if proxies == nil {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), requestHostKey{}, proxies.Host(r)))
+}
+
+// RequestHost returns the original host of the request, as resolved by the TrustedProxies of
+// [WithTenantConfig], or the Host of the request if none are configured. The tenant getters of
+// this package, such as [DefaultTenantFromSubdomain], use it instead of the Host of the request.
+func RequestHost(r *http.Request) string {
+	if host, ok := r.Context().Value(requestHostKey{}).(string); ok {
+		return host
+	}
+	return r.Host
+}
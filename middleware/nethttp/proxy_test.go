@@ -0,0 +1,154 @@
+package nethttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewTrustedProxies(t *testing.T) {
+	if _, err := NewTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1", "2001:db8::/32"}); err != nil {
+		t.Errorf("NewTrustedProxies() error = %v", err)
+	}
+	if _, err := NewTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("NewTrustedProxies() error = nil, want an error for an invalid CIDR")
+	}
+}
+
+func TestTrustedProxies_Host(t *testing.T) {
+	proxies, err := NewTrustedProxies([]string{"10.0.0.0/8", "2001:db8::/32"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	xffOnly, err := NewTrustedProxies([]string{"10.0.0.0/8"}, WithHostHeaders(HeaderXForwardedHost))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		proxies    *TrustedProxies
+		remoteAddr string
+		header     http.Header
+		want       string
+	}{
+		{
+			name:       "untrusted remote address",
+			proxies:    proxies,
+			remoteAddr: "203.0.113.7:1234",
+			header:     http.Header{"X-Forwarded-Host": {"acme.example.com"}},
+			want:       "svc.internal",
+		},
+		{
+			name:       "nil proxies",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-Host": {"acme.example.com"}},
+			want:       "svc.internal",
+		},
+		{
+			name:       "x-forwarded-host",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-Host": {"acme.example.com"}},
+			want:       "acme.example.com",
+		},
+		{
+			name:       "x-forwarded-host appended to by proxy",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-Host": {"evil.example.com, acme.example.com"}},
+			want:       "acme.example.com",
+		},
+		{
+			name:       "ipv6 remote address",
+			proxies:    proxies,
+			remoteAddr: "[2001:db8::1]:1234",
+			header:     http.Header{"X-Forwarded-Host": {"acme.example.com"}},
+			want:       "acme.example.com",
+		},
+		{
+			name:       "forwarded",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"Forwarded": {`for=203.0.113.7;host=acme.example.com;proto=https`}},
+			want:       "acme.example.com",
+		},
+		{
+			name:       "forwarded takes precedence",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:1234",
+			header: http.Header{
+				"Forwarded":        {`for=203.0.113.7;host="acme.example.com:8443"`},
+				"X-Forwarded-Host": {"other.example.com"},
+			},
+			want: "acme.example.com:8443",
+		},
+		{
+			name:       "forwarded through proxy chain",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.2:1234",
+			header: http.Header{"Forwarded": {
+				`for=198.51.100.1;host=evil.example.com`,
+				`for=203.0.113.7;host=acme.example.com, for="[2001:db8::5]";host=svc.internal`,
+			}},
+			want: "acme.example.com",
+		},
+		{
+			name:       "forwarded without host",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"Forwarded": {`for=203.0.113.7`}, "X-Forwarded-Host": {"acme.example.com"}},
+			want:       "acme.example.com",
+		},
+		{
+			name:       "forwarded ignored",
+			proxies:    xffOnly,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"Forwarded": {`for=203.0.113.7;host=other.example.com`}, "X-Forwarded-Host": {"acme.example.com"}},
+			want:       "acme.example.com",
+		},
+		{
+			name:       "invalid host",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-Host": {"acme.example.com/evil"}},
+			want:       "svc.internal",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = "svc.internal"
+			req.RemoteAddr = tt.remoteAddr
+			req.Header = tt.header
+			if got := tt.proxies.Host(req); got != tt.want {
+				t.Errorf("Host() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTenant_trustedProxies(t *testing.T) {
+	proxies, err := NewTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := WithTenant(WithTenantConfig{TrustedProxies: proxies})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Context().Value(TenantKey).(string)))
+	}))
+
+	for remoteAddr, want := range map[string]int{"10.0.0.1:1234": http.StatusOK, "203.0.113.7:1234": http.StatusBadRequest} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = "svc"
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(HeaderXForwardedHost, "acme.example.com")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("status = %d, want %d", rec.Code, want)
+		}
+		if want == http.StatusOK && rec.Body.String() != "acme" {
+			t.Errorf("tenant = %q, want %q", rec.Body.String(), "acme")
+		}
+	}
+}
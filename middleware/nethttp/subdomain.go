@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"strings"
+
+	"golang.org/x/net/publicsuffix"
 )
 
 // Errors.
@@ -31,6 +34,8 @@ func (w *wrapped) Unwrap() error {
 type SubdomainOptions struct {
 	DisallowedSubdomains []string // Disallowed subdomains
 	DisallowedPrefixes   []string // Disallowed prefixes
+	BaseDomains          []string // Domains the subdomain must sit under
+	PublicSuffixList     bool     // Resolve the registrable domain with the Public Suffix List
 }
 
 func (o *SubdomainOptions) apply(opts ...SubdomainOption) {
@@ -56,6 +61,36 @@ func WithDisallowedPrefixes(prefixes ...string) SubdomainOption {
 	}
 }
 
+// WithBaseDomains sets the base domains the subdomain must sit under, such as example.com or
+// example.co.uk. The subdomain is the label immediately left of the longest base domain the host
+// is under, such as acme in api.acme.example.com, and hosts under none of them are rejected. It
+// takes precedence over [WithPublicSuffixList].
+func WithBaseDomains(domains ...string) SubdomainOption {
+	return func(o *SubdomainOptions) {
+		o.BaseDomains = domains
+	}
+}
+
+// WithPublicSuffixList resolves the registrable domain of the host, such as example.co.uk, with
+// the Public Suffix List embedded in [golang.org/x/net/publicsuffix]. The subdomain is the label
+// immediately left of the registrable domain, such as acme in api.acme.example.co.uk, and apex
+// hosts have none.
+func WithPublicSuffixList() SubdomainOption {
+	return func(o *SubdomainOptions) {
+		o.PublicSuffixList = true
+	}
+}
+
+// labelBelow returns the label immediately left of the domain in the hostname, if the hostname is
+// a subdomain of the domain.
+func labelBelow(hostname, domain string) (string, bool) {
+	rest, found := strings.CutSuffix(hostname, "."+domain)
+	if !found || rest == "" {
+		return "", false
+	}
+	return rest[strings.LastIndex(rest, ".")+1:], true
+}
+
 // isIPAddress checks if the given host is an IP address (IPv4 or IPv6).
 func isIPAddress(host string) bool {
 	return net.ParseIP(host) != nil
@@ -69,6 +104,11 @@ func isIPAddress(host string) bool {
 // The host is expected to be in the following format:
 //
 //	subdomain.domain.tld[:port]
+//
+// By default, the first label of a host with three or more labels is the subdomain, which is
+// wrong for hosts under multi-label public suffixes, such as acme.example.co.uk, or for deeper
+// hosts, such as api.acme.example.com. Use [WithBaseDomains] or [WithPublicSuffixList] to
+// extract the label immediately left of the base or registrable domain instead.
 func ExtractSubdomain(host string, opts ...SubdomainOption) (subdomain string, err error) {
 	// IPv6 address
 	if strings.HasPrefix(host, "[") {
@@ -86,16 +126,45 @@ func ExtractSubdomain(host string, opts ...SubdomainOption) (subdomain string, e
 		return "", &wrapped{ErrInvalidHost, host, "IPv4 addresses are not allowed"}
 	}
 
-	// Split host into hostParts (subdomain, domain, tld)
-	hostParts := strings.SplitN(hostNoPort, ".", 3)
-	if len(hostParts) < 3 {
-		return "", &wrapped{ErrInvalidHost, host, "no subdomain found"}
-	}
-	subdomain = hostParts[0]
-
 	options := new(SubdomainOptions)
 	options.apply(opts...)
 
+	switch hostname := strings.ToLower(strings.TrimSuffix(hostNoPort, ".")); {
+	case len(options.BaseDomains) > 0:
+		var base string
+		for _, domain := range options.BaseDomains {
+			domain = strings.ToLower(strings.Trim(domain, "."))
+			if (hostname == domain || strings.HasSuffix(hostname, "."+domain)) && len(domain) > len(base) {
+				base = domain
+			}
+		}
+		if base == "" {
+			return "", &wrapped{ErrInvalidHost, host, "host is not under a base domain"}
+		}
+		label, ok := labelBelow(hostname, base)
+		if !ok {
+			return "", &wrapped{ErrInvalidHost, host, "no subdomain found"}
+		}
+		subdomain = label
+	case options.PublicSuffixList:
+		domain, err := publicsuffix.EffectiveTLDPlusOne(hostname)
+		if err != nil {
+			return "", &wrapped{ErrInvalidHost, host, err.Error()}
+		}
+		label, ok := labelBelow(hostname, domain)
+		if !ok {
+			return "", &wrapped{ErrInvalidHost, host, "no subdomain found"}
+		}
+		subdomain = label
+	default:
+		// Split host into hostParts (subdomain, domain, tld)
+		hostParts := strings.SplitN(hostNoPort, ".", 3)
+		if len(hostParts) < 3 {
+			return "", &wrapped{ErrInvalidHost, host, "no subdomain found"}
+		}
+		subdomain = hostParts[0]
+	}
+
 	for _, disallowed := range options.DisallowedSubdomains {
 		if subdomain == disallowed {
 			return "", &wrapped{ErrInvalidSubdomain, host, fmt.Sprintf("subdomain %q is disallowed", disallowed)}
@@ -110,3 +179,16 @@ func ExtractSubdomain(host string, opts ...SubdomainOption) (subdomain string, e
 
 	return subdomain, nil
 }
+
+// TenantFromSubdomain returns a tenant getter that extracts the tenant from the subdomain of the
+// request host, as returned by [RequestHost], with the given options, such as [WithBaseDomains]. [DefaultTenantFromSubdomain]
+// uses no options.
+func TenantFromSubdomain(opts ...SubdomainOption) func(r *http.Request) (string, error) {
+	return func(r *http.Request) (string, error) {
+		tenant, err := ExtractSubdomain(RequestHost(r), opts...)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrTenantMissing, err)
+		}
+		return tenant, nil
+	}
+}
@@ -2,6 +2,8 @@ package nethttp
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -24,6 +26,20 @@ func TestExtractSubdomain(t *testing.T) {
 		{"invalid host:IPv6 RFC 3986 (with port)", "[fe80::1]:8080", nil, "", true, ErrInvalidHost},
 		{"invalid subdomain:disallowed prefix", "pg_sub.example.com", []SubdomainOption{WithDisallowedPrefixes("pg_")}, "", true, ErrInvalidSubdomain},
 		{"invalid subdomain:disallowed subdomain", "blacklisted.example.com", []SubdomainOption{WithDisallowedSubdomains("blacklisted")}, "", true, ErrInvalidSubdomain},
+		{"psl:multi-label suffix", "acme.example.co.uk", []SubdomainOption{WithPublicSuffixList()}, "acme", false, nil},
+		{"psl:multi-label suffix (with port)", "acme.example.com.au:8080", []SubdomainOption{WithPublicSuffixList()}, "acme", false, nil},
+		{"psl:deeper host", "api.acme.example.com", []SubdomainOption{WithPublicSuffixList()}, "acme", false, nil},
+		{"psl:case and trailing dot", "ACME.Example.co.uk.", []SubdomainOption{WithPublicSuffixList()}, "acme", false, nil},
+		{"psl:apex under multi-label suffix", "example.co.uk", []SubdomainOption{WithPublicSuffixList()}, "", true, ErrInvalidHost},
+		{"psl:public suffix", "co.uk", []SubdomainOption{WithPublicSuffixList()}, "", true, ErrInvalidHost},
+		{"psl:disallowed subdomain", "www.example.co.uk", []SubdomainOption{WithPublicSuffixList(), WithDisallowedSubdomains("www")}, "", true, ErrInvalidSubdomain},
+		{"base:multi-label suffix", "acme.example.co.uk", []SubdomainOption{WithBaseDomains("example.com", "example.co.uk")}, "acme", false, nil},
+		{"base:deeper host", "api.acme.example.com", []SubdomainOption{WithBaseDomains("example.com")}, "acme", false, nil},
+		{"base:longest base domain", "acme.eu.example.com", []SubdomainOption{WithBaseDomains("example.com", "eu.example.com")}, "acme", false, nil},
+		{"base:takes precedence over psl", "acme.app.example.com", []SubdomainOption{WithPublicSuffixList(), WithBaseDomains("app.example.com")}, "acme", false, nil},
+		{"base:apex", "example.co.uk", []SubdomainOption{WithBaseDomains("example.co.uk")}, "", true, ErrInvalidHost},
+		{"base:not under base domain", "acme.example.org", []SubdomainOption{WithBaseDomains("example.com")}, "", true, ErrInvalidHost},
+		{"base:suffix is not a label boundary", "acme.badexample.com", []SubdomainOption{WithBaseDomains("example.com")}, "", true, ErrInvalidHost},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -42,3 +58,18 @@ func TestExtractSubdomain(t *testing.T) {
 		})
 	}
 }
+
+func TestTenantFromSubdomain(t *testing.T) {
+	getter := TenantFromSubdomain(WithPublicSuffixList())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "acme.example.co.uk"
+	if tenant, err := getter(req); tenant != "acme" || err != nil {
+		t.Errorf("TenantFromSubdomain() = %q, %v, want %q, nil", tenant, err, "acme")
+	}
+
+	req.Host = "example.co.uk"
+	if _, err := getter(req); !errors.Is(err, ErrTenantMissing) || !errors.Is(err, ErrInvalidHost) {
+		t.Errorf("TenantFromSubdomain() error = %v, want %v and %v", err, ErrTenantMissing, ErrInvalidHost)
+	}
+}
@@ -0,0 +1,263 @@
+package nethttp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+)
+
+// DefaultReadOnly reports whether the request only reads data, based on its method (GET or HEAD).
+func DefaultReadOnly(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// DefaultWithTransactionConfig is the default configuration for the WithTransaction middleware.
+// It uses the default skipper and context key, responds to errors with [ProblemErrorHandler], and
+// opens read-write transactions for all requests.
+var DefaultWithTransactionConfig = WithTransactionConfig{
+	Skipper:      DefaultSkipper,
+	ContextKey:   TenantKey,
+	ErrorHandler: ProblemErrorHandler,
+}
+
+// WithTransactionConfig represents the configuration options for the transaction middleware in
+// net/http.
+type WithTransactionConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper func(r *http.Request) bool
+
+	// ContextKey is the key of the tenant in the request context, as set by [WithTenant].
+	ContextKey fmt.Stringer
+
+	// TxOptions are the options of the transaction; optional.
+	TxOptions *sql.TxOptions
+
+	// ReadOnly reports whether the transaction of the request should be read-only, such as
+	// [DefaultReadOnly]; optional.
+	ReadOnly func(r *http.Request) bool
+
+	// ErrorHandler is a callback function that is called when the transaction cannot be opened or
+	// committed. The response has not been written then, and the response of the handler is
+	// discarded.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// WithTransaction is a middleware function that runs each request within a transaction scoped to
+// the tenant of the request, as set by [WithTenant], which must therefore run first. The
+// tenant-bound database is stored in the request context, and retrieved with [DBFromContext].
+//
+// The transaction is committed if the handler responds with a 2xx or 3xx status code, and rolled
+// back if it responds with any other status code or panics. The panic is propagated after the
+// rollback. The transaction ends before the status line of the response is written, so that the
+// client is not told that the request succeeded if the commit fails: the response of the handler
+// is then discarded, and the error handler responds instead.
+//
+// Example:
+//
+//	handler := nethttp.WithTenant(nethttp.DefaultWithTenantConfig)(
+//		nethttp.WithTransaction(db, nethttp.WithTransactionConfig{ReadOnly: nethttp.DefaultReadOnly})(mux),
+//	)
+//
+//	mux.HandleFunc("/books", func(w http.ResponseWriter, r *http.Request) {
+//		tx, _ := nethttp.DBFromContext(r.Context())
+//		var books []Book
+//		if err := tx.Find(&books).Error; err != nil {
+//			http.Error(w, err.Error(), http.StatusInternalServerError)
+//			return
+//		}
+//		...
+//	})
+func WithTransaction(db *multitenancy.DB, config WithTransactionConfig) func(http.Handler) http.Handler {
+	if config.Skipper == nil {
+		config.Skipper = DefaultWithTransactionConfig.Skipper
+	}
+
+	if config.ContextKey == nil {
+		config.ContextKey = DefaultWithTransactionConfig.ContextKey
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultWithTransactionConfig.ErrorHandler
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if config.Skipper(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			tenant, _ := r.Context().Value(config.ContextKey).(string)
+			readOnly := config.ReadOnly != nil && config.ReadOnly(r)
+			tx, err := BeginTenantTx(r.Context(), db, tenant, config.TxOptions, readOnly)
+			if err != nil {
+				config.ErrorHandler(w, r, err)
+				return
+			}
+			defer func() {
+				if p := recover(); p != nil {
+					_ = tx.Rollback()
+					panic(p)
+				}
+			}()
+
+			tw := NewTxResponseWriter(w, tx, nil, func(err error) { config.ErrorHandler(w, r, err) })
+			next.ServeHTTP(tw, r.WithContext(context.WithValue(r.Context(), DBKey, tx.DB)))
+			_ = tw.End(http.StatusOK) // the handler may have written no response
+		})
+	}
+}
+
+// DBFromContext returns the tenant-bound database stored in the context by [WithTransaction].
+func DBFromContext(ctx context.Context) (*multitenancy.DB, bool) {
+	db, ok := ctx.Value(DBKey).(*multitenancy.DB)
+	return db, ok
+}
+
+// TenantTx is a transaction scoped to a tenant, as opened by [BeginTenantTx]. It is used by the
+// transaction middleware of the framework packages. Not intended for direct use in application
+// code.
+type TenantTx struct {
+	// DB is the tenant-bound database of the transaction.
+	DB *multitenancy.DB
+
+	reset func() error
+	done  bool
+}
+
+// BeginTenantTx begins a transaction and scopes it to the tenant. Not intended for direct use in
+// application code.
+func BeginTenantTx(ctx context.Context, db *multitenancy.DB, tenant string, opts *sql.TxOptions, readOnly bool) (*TenantTx, error) {
+	if tenant == "" {
+		return nil, fmt.Errorf("%w: no tenant in request context", ErrTenantInvalid)
+	}
+	if readOnly {
+		txOpts := sql.TxOptions{ReadOnly: true}
+		if opts != nil {
+			txOpts.Isolation = opts.Isolation
+		}
+		opts = &txOpts
+	}
+	var txOpts []*sql.TxOptions
+	if opts != nil {
+		txOpts = append(txOpts, opts)
+	}
+	tx := db.WithContext(ctx).Begin(txOpts...)
+	if err := tx.Error; err != nil {
+		return nil, fmt.Errorf("failed to begin transaction for tenant %s: %w", tenant, err)
+	}
+	reset, err := tx.UseTenant(ctx, tenant)
+	if err != nil {
+		tx.DB.Rollback()
+		return nil, fmt.Errorf("failed to use tenant %s: %w", tenant, err)
+	}
+	return &TenantTx{DB: tx, reset: reset}, nil
+}
+
+// End commits the transaction if commit is true, and rolls it back otherwise. Subsequent calls
+// are no-ops.
+func (t *TenantTx) End(commit bool) error {
+	if !commit {
+		return t.Rollback()
+	}
+	if t.done {
+		return nil
+	}
+	t.done = true
+	if err := t.reset(); err != nil {
+		t.DB.DB.Rollback()
+		return fmt.Errorf("failed to reset tenant: %w", err)
+	}
+	if err := t.DB.DB.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Rollback rolls back the transaction. Subsequent calls are no-ops.
+func (t *TenantTx) Rollback() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	_ = t.reset() // the transaction may be aborted
+	return t.DB.DB.Rollback().Error
+}
+
+// TxResponseWriter is an [http.ResponseWriter] ending a [TenantTx] before the status line of the
+// response is written. It is used by the transaction middleware of the framework packages. Not
+// intended for direct use in application code.
+type TxResponseWriter struct {
+	http.ResponseWriter
+
+	tx      *TenantTx
+	commit  func(status int) bool
+	onError func(err error)
+	ended   bool
+	err     error
+}
+
+// NewTxResponseWriter returns a [TxResponseWriter] wrapping w. The transaction is committed if
+// commit reports true for the final status code of the response, or, if commit is nil, if the
+// status code is 2xx or 3xx; it is rolled back otherwise. If the commit fails, onError is called to
+// respond with the error instead, and later writes fail with the error. Not intended for direct
+// use in application code.
+func NewTxResponseWriter(w http.ResponseWriter, tx *TenantTx, commit func(status int) bool, onError func(err error)) *TxResponseWriter {
+	if commit == nil {
+		commit = func(status int) bool { return status >= http.StatusOK && status < http.StatusBadRequest }
+	}
+	return &TxResponseWriter{ResponseWriter: w, tx: tx, commit: commit, onError: onError}
+}
+
+// End ends the transaction for a response with the given final status code, unless it has ended
+// already. It returns the error of the commit, if it failed.
+func (w *TxResponseWriter) End(status int) error {
+	if !w.ended {
+		w.ended = true
+		if !w.commit(status) {
+			_ = w.tx.Rollback()
+		} else if w.err = w.tx.End(true); w.err != nil {
+			// The headers describing the body of the handler do not describe the error response.
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.onError(w.err)
+		}
+	}
+	return w.err
+}
+
+// WriteHeader ends the transaction before writing a final status code. Informational (1xx) status
+// codes precede the final one, and are written as is.
+func (w *TxResponseWriter) WriteHeader(code int) {
+	if code >= http.StatusOK && w.End(code) != nil {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write ends the transaction before writing the body, with an implicit 200 OK status code if none
+// was written.
+func (w *TxResponseWriter) Write(b []byte) (int, error) {
+	if err := w.End(http.StatusOK); err != nil {
+		return 0, err
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush ends the transaction before flushing the response, with an implicit 200 OK status code if
+// none was written.
+func (w *TxResponseWriter) Flush() {
+	if w.End(http.StatusOK) != nil {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying [http.ResponseWriter], for [http.ResponseController].
+func (w *TxResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,224 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/drivertest/fakedb"
+)
+
+func TestWithTransaction(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		tenant       string
+		handler      http.HandlerFunc
+		wantCode     int
+		wantCommit   bool
+		wantRollback bool
+		wantReadOnly bool
+	}{
+		{
+			name:   "commit on success",
+			method: http.MethodPost,
+			tenant: "tenant1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			},
+			wantCode:   http.StatusCreated,
+			wantCommit: true,
+		},
+		{
+			name:       "commit on implicit ok",
+			method:     http.MethodPost,
+			tenant:     "tenant1",
+			handler:    func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("ok")) },
+			wantCode:   http.StatusOK,
+			wantCommit: true,
+		},
+		{
+			name:   "commit on redirect",
+			method: http.MethodPost,
+			tenant: "tenant1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/", http.StatusSeeOther)
+			},
+			wantCode:   http.StatusSeeOther,
+			wantCommit: true,
+		},
+		{
+			name:   "rollback on client error",
+			method: http.MethodPost,
+			tenant: "tenant1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "bad request", http.StatusBadRequest)
+			},
+			wantCode:     http.StatusBadRequest,
+			wantRollback: true,
+		},
+		{
+			name:   "rollback on server error",
+			method: http.MethodPost,
+			tenant: "tenant1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "oops", http.StatusInternalServerError)
+			},
+			wantCode:     http.StatusInternalServerError,
+			wantRollback: true,
+		},
+		{
+			name:   "commit on body after informational response",
+			method: http.MethodPost,
+			tenant: "tenant1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusEarlyHints)
+				_, _ = w.Write([]byte("ok"))
+			},
+			wantCommit: true,
+		},
+		{
+			name:   "rollback on error after informational response",
+			method: http.MethodPost,
+			tenant: "tenant1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusEarlyHints)
+				w.WriteHeader(http.StatusConflict)
+			},
+			wantRollback: true,
+		},
+		{
+			name:         "read-only GET",
+			method:       http.MethodGet,
+			tenant:       "tenant1",
+			handler:      func(w http.ResponseWriter, r *http.Request) {},
+			wantCode:     http.StatusOK,
+			wantCommit:   true,
+			wantReadOnly: true,
+		},
+		{
+			name:     "no tenant",
+			method:   http.MethodGet,
+			handler:  func(w http.ResponseWriter, r *http.Request) { t.Error("handler called") },
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, d, pool := fakedb.New(t)
+			var gotDB *multitenancy.DB
+			handler := WithTransaction(db, WithTransactionConfig{ReadOnly: DefaultReadOnly})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotDB, _ = DBFromContext(r.Context())
+				tt.handler(w, r)
+			}))
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.tenant != "" {
+				req = req.WithContext(context.WithValue(req.Context(), TenantKey, tt.tenant))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			// The recorder reports informational status codes as final ones, so they are not checked.
+			if tt.wantCode != 0 && rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := pool.Committed == 1; got != tt.wantCommit {
+				t.Errorf("committed = %d, want commit %v", pool.Committed, tt.wantCommit)
+			}
+			if got := pool.RolledBack == 1; got != tt.wantRollback {
+				t.Errorf("rolled back = %d, want rollback %v", pool.RolledBack, tt.wantRollback)
+			}
+			if tt.tenant == "" {
+				return
+			}
+			if gotDB == nil {
+				t.Fatal("DBFromContext() returned no database")
+			}
+			if len(d.Tenants) != 1 || d.Tenants[0] != tt.tenant || d.Resets != 1 {
+				t.Errorf("tenants = %v, resets = %d, want [%s], 1", d.Tenants, d.Resets, tt.tenant)
+			}
+			readOnly := len(pool.Opts) == 1 && pool.Opts[0] != nil && pool.Opts[0].ReadOnly
+			if readOnly != tt.wantReadOnly {
+				t.Errorf("read-only = %v, want %v", readOnly, tt.wantReadOnly)
+			}
+		})
+	}
+}
+
+func TestWithTransaction_Panic(t *testing.T) {
+	db, d, pool := fakedb.New(t)
+	handler := WithTransaction(db, WithTransactionConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(errors.New("boom"))
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), TenantKey, "tenant1"))
+
+	defer func() {
+		if p := recover(); p == nil {
+			t.Fatal("panic was not propagated")
+		}
+		if pool.RolledBack != 1 || pool.Committed != 0 || d.Resets != 1 {
+			t.Errorf("rolled back = %d, committed = %d, resets = %d, want 1, 0, 1", pool.RolledBack, pool.Committed, d.Resets)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestWithTransaction_CommitError(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "explicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte("created"))
+			},
+		},
+		{
+			name:    "implicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("ok")) },
+		},
+		{
+			name:    "flush",
+			handler: func(w http.ResponseWriter, r *http.Request) { _ = http.NewResponseController(w).Flush() },
+		},
+		{
+			name:    "no response",
+			handler: func(w http.ResponseWriter, r *http.Request) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _, pool := fakedb.New(t)
+			pool.CommitErr = errors.New("serialization failure")
+			handler := WithTransaction(db, WithTransactionConfig{})(tt.handler)
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = req.WithContext(context.WithValue(req.Context(), TenantKey, "tenant1"))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+			if got := rec.Header().Get("Content-Type"); got != ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", got, ProblemContentType)
+			}
+			if body := rec.Body.String(); strings.Contains(body, "created") || strings.Contains(body, "ok") {
+				t.Errorf("body = %q, want the response of the handler discarded", body)
+			}
+		})
+	}
+}
+
+func TestDBFromContext(t *testing.T) {
+	if _, ok := DBFromContext(context.Background()); ok {
+		t.Error("DBFromContext() ok = true, want false")
+	}
+}
@@ -0,0 +1,168 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
+)
+
+// MigrateTenants migrates the registered tenant-specific models for the given tenants, in order,
+// as a resumable migration run. The run, its ordered list of tenants and the status of each tenant
+// are persisted in the public schema, and the identifier of the run is returned.
+//
+// Migration stops at the first tenant that fails to migrate. The run identifier is returned
+// alongside the error, so that the run can be continued with [DB.ResumeMigrationRun] once the
+// cause has been addressed, or after the process was interrupted.
+//
+// Not safe for concurrent use with the same run.
+func (db *DB) MigrateTenants(ctx context.Context, tenantIDs []string, opts ...migrator.MigrateOption) (runID string, err error) {
+	fingerprint, err := db.tenantModelsFingerprint()
+	if err != nil {
+		return "", err
+	}
+	run, err := migrator.CreateRun(db.DB.WithContext(ctx), fingerprint, tenantIDs)
+	if err != nil {
+		return "", gmterrors.New(fmt.Errorf("failed to create migration run: %w", err))
+	}
+	return run.ID, db.ResumeMigrationRun(ctx, run.ID, opts...)
+}
+
+// ResumeMigrationRun continues the migration run with the given identifier, as returned by
+// [DB.MigrateTenants]. Tenants that have already been migrated within the run under the current
+// model fingerprint are skipped; all other tenants are migrated in the original order.
+// It returns an error wrapping [migrator.ErrRunNotFound] if the run does not exist.
+//
+// Not safe for concurrent use with the same run.
+func (db *DB) ResumeMigrationRun(ctx context.Context, runID string, opts ...migrator.MigrateOption) (err error) {
+	tx := db.DB.WithContext(ctx)
+	run, tenants, err := migrator.LoadRun(tx, runID)
+	if err != nil {
+		return gmterrors.New(fmt.Errorf("failed to load migration run %q: %w", runID, err))
+	}
+	fingerprint, err := db.tenantModelsFingerprint()
+	if err != nil {
+		return err
+	}
+
+	run.Fingerprint = fingerprint
+	run.Status = migrator.RunStatusRunning
+	if err := migrator.UpdateRun(tx, run); err != nil {
+		return gmterrors.New(fmt.Errorf("failed to update migration run %q: %w", runID, err))
+	}
+	defer func() {
+		run.Status = migrator.RunStatusCompleted
+		if err != nil {
+			run.Status = migrator.RunStatusFailed
+		}
+		// The context may have been cancelled; the final status is recorded regardless.
+		if updateErr := migrator.UpdateRun(db.DB, run); updateErr != nil {
+			err = errors.Join(err, gmterrors.New(fmt.Errorf("failed to update migration run %q: %w", runID, updateErr)))
+		}
+	}()
+
+	for i := range tenants {
+		tenant := &tenants[i]
+		if tenant.Status == migrator.RunStatusCompleted && tenant.Fingerprint == fingerprint {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		migrateErr := db.MigrateTenantModels(ctx, tenant.TenantID, opts...)
+		tenant.Status, tenant.Fingerprint, tenant.Error = migrator.RunStatusCompleted, fingerprint, ""
+		if migrateErr != nil {
+			tenant.Status, tenant.Error = migrator.RunStatusFailed, migrateErr.Error()
+		}
+		if updateErr := migrator.UpdateRunTenant(db.DB, tenant); updateErr != nil {
+			return errors.Join(migrateErr, gmterrors.New(fmt.Errorf("failed to update migration run %q for tenant %q: %w", runID, tenant.TenantID, updateErr)))
+		}
+		if migrateErr != nil {
+			return migrateErr
+		}
+	}
+	return nil
+}
+
+// SubscribeModelGroups subscribes the tenant to the given model groups, as declared by tenant
+// models implementing [driver.ModelGrouper]. The tables of the new groups are created by the
+// next call to [DB.MigrateTenantModels], which only migrates the groups that changed.
+//
+// Every tenant subscribes to [driver.DefaultModelGroup]. An error is returned if any of the
+// groups has no registered models.
+func (db *DB) SubscribeModelGroups(ctx context.Context, tenantID string, groups ...string) error {
+	if err := db.validateModelGroups(groups); err != nil {
+		return err
+	}
+	if err := migrator.SubscribeModelGroups(db.DB.WithContext(ctx), tenantID, groups...); err != nil {
+		return gmterrors.New(fmt.Errorf("failed to subscribe tenant %q to model groups: %w", tenantID, err))
+	}
+	return nil
+}
+
+// UnsubscribeModelGroups removes the tenant's subscriptions to the given model groups. Subsequent
+// migrations no longer migrate the models of these groups for the tenant. Existing tables are
+// left in place. [driver.DefaultModelGroup] cannot be unsubscribed from.
+func (db *DB) UnsubscribeModelGroups(ctx context.Context, tenantID string, groups ...string) error {
+	if slices.Contains(groups, driver.DefaultModelGroup) {
+		return gmterrors.New(fmt.Errorf("cannot unsubscribe from the %q model group", driver.DefaultModelGroup))
+	}
+	if err := migrator.UnsubscribeModelGroups(db.DB.WithContext(ctx), tenantID, groups...); err != nil {
+		return gmterrors.New(fmt.Errorf("failed to unsubscribe tenant %q from model groups: %w", tenantID, err))
+	}
+	return nil
+}
+
+// TenantModelGroups returns the sorted names of the model groups the tenant subscribes to.
+func (db *DB) TenantModelGroups(ctx context.Context, tenantID string) ([]string, error) {
+	stored, err := migrator.LoadModelGroups(db.DB.WithContext(ctx), tenantID)
+	if err != nil {
+		return nil, gmterrors.New(fmt.Errorf("failed to load model groups for tenant %q: %w", tenantID, err))
+	}
+	groups := make([]string, 0, len(stored))
+	for group := range stored {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+	return groups, nil
+}
+
+// validateModelGroups checks that each of the groups has registered models, if the dialector
+// exposes its registered models.
+func (db *DB) validateModelGroups(groups []string) error {
+	provider, ok := db.Dialector.(driver.ModelRegistryProvider)
+	if !ok || provider.ModelRegistry() == nil {
+		return nil
+	}
+	registered := provider.ModelRegistry().TenantModelGroups()
+	var errs []error
+	for _, group := range groups {
+		if !slices.Contains(registered, group) {
+			errs = append(errs, fmt.Errorf("unknown model group %q", group))
+		}
+	}
+	if len(errs) > 0 {
+		return gmterrors.New(errors.Join(errs...))
+	}
+	return nil
+}
+
+// tenantModelsFingerprint returns the fingerprint of the registered tenant models and SQL objects,
+// or an empty string if the dialector does not expose its registered models.
+func (db *DB) tenantModelsFingerprint() (string, error) {
+	provider, ok := db.Dialector.(driver.ModelRegistryProvider)
+	if !ok || provider.ModelRegistry() == nil {
+		return "", nil
+	}
+	registry := provider.ModelRegistry()
+	fingerprint, err := migrator.Fingerprint(db.DB, driver.ModelsToInterfaces(registry.RegisteredTenantModels())...)
+	if err != nil {
+		return "", gmterrors.New(fmt.Errorf("failed to compute model fingerprint: %w", err))
+	}
+	return migrator.CombineFingerprint(fingerprint, registry.TenantObjects()...), nil
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"cmp"
 	"fmt"
 	"time"
 
@@ -12,15 +13,24 @@ import (
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/logext"
+	gmtmigrator "github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
 )
 
 type (
 	// Options provides configuration options with multitenancy support.
 	// By default, retry is enabled. To disable retry, set DisableRetry to true.
 	// Note that the retry logic is only applied to migrations.
+	//
+	// When SafeMigration is true, tenant migrations are applied to a shadow copy of the tenant
+	// database and swapped into place atomically; see [Migrator.MigrateTenantModels].
+	//
+	// PublicSchema is the database holding the tables of shared models, defaulting to
+	// [driver.PublicSchemaName].
 	Options struct {
-		DisableRetry bool            `json:"gmt_disable_retry" mapstructure:"gmt_disable_retry"` // Whether to disable retry.
-		Retry        backoff.Options `json:",inline"           mapstructure:",squash"`           // Retry options.
+		DisableRetry  bool            `json:"gmt_disable_retry"  mapstructure:"gmt_disable_retry"`  // Whether to disable retry.
+		SafeMigration bool            `json:"gmt_safe_migration" mapstructure:"gmt_safe_migration"` // Whether to migrate tenants via a shadow database.
+		PublicSchema  string          `json:"gmt_public_schema"  mapstructure:"gmt_public_schema"`  // Database holding the shared tables.
+		Retry         backoff.Options `json:",inline"            mapstructure:",squash"`            // Retry options.
 	}
 
 	// Option is a function that modifies an [Options] instance.
@@ -69,7 +79,7 @@ func Open(dsn string) gorm.Dialector {
 	options.apply()
 	return &Dialector{
 		Dialector: mysql.Open(dsn).(*mysql.Dialector),
-		registry:  &driver.ModelRegistry{},
+		registry:  newModelRegistry(options.PublicSchema),
 		logger:    logext.Default(),
 		options:   &options,
 	}
@@ -81,12 +91,22 @@ func New(config Config, opts ...Option) gorm.Dialector {
 	options.apply(opts...)
 	return &Dialector{
 		Dialector: mysql.New(config.Config).(*mysql.Dialector),
-		registry:  &driver.ModelRegistry{},
+		registry:  newModelRegistry(options.PublicSchema),
 		logger:    logext.Default(),
 		options:   options,
 	}
 }
 
+// Initialize implements [gorm.Dialector]. Along with initializing the embedded dialector, it
+// registers the callbacks directing the statements of the registered shared models that declare
+// their tenancy to the public schema (see [driver.RegisterTenancyCallbacks]).
+func (dialector Dialector) Initialize(db *gorm.DB) error {
+	if err := dialector.Dialector.Initialize(db); err != nil {
+		return err
+	}
+	return driver.RegisterTenancyCallbacks(db, dialector.registry)
+}
+
 // Migrator returns a [gorm.Migrator] implementation for the Dialector.
 func (dialector Dialector) Migrator(db *gorm.DB) gorm.Migrator {
 	return &Migrator{
@@ -104,21 +124,95 @@ func (dialector Dialector) Migrator(db *gorm.DB) gorm.Migrator {
 }
 
 // RegisterModels registers the given models with the dialector for multitenancy support.
-// Not safe for concurrent use by multiple goroutines.
+// Models are added to the models already registered; a model replaces any registered model
+// with the same table name. Safe for concurrent use by multiple goroutines.
 func (dialector *Dialector) RegisterModels(models ...driver.TenantTabler) error {
-	registry, err := driver.NewModelRegistry(models...)
-	if err != nil {
+	if err := dialector.registry.Register(models...); err != nil {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to register models: %w", err))
 	}
+	return nil
+}
+
+// UnregisterModels removes the given models from the models registered with the dialector.
+// Safe for concurrent use by multiple goroutines.
+func (dialector *Dialector) UnregisterModels(models ...driver.TenantTabler) {
+	dialector.registry.Unregister(models...)
+}
 
-	dialector.registry = registry
+// RegisterObjects registers the given SQL objects with the dialector, to be created alongside the
+// registered models (see [driver.SQLObject]). Safe for concurrent use by multiple goroutines.
+func (dialector *Dialector) RegisterObjects(objects ...driver.SQLObject) error {
+	if err := dialector.registry.RegisterObjects(objects...); err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to register SQL objects: %w", err))
+	}
 	return nil
 }
 
+// UnregisterObjects removes the SQL objects with the given names from the dialector.
+// Safe for concurrent use by multiple goroutines.
+func (dialector *Dialector) UnregisterObjects(names ...string) {
+	dialector.registry.UnregisterObjects(names...)
+}
+
+var _ driver.ModelRegistryProvider = new(Dialector)
+var _ driver.PublicSchemaProvider = new(Dialector)
+
+// newModelRegistry returns an empty model registry validating shared models against the public schema.
+func newModelRegistry(publicSchema string) *driver.ModelRegistry {
+	registry := &driver.ModelRegistry{}
+	registry.SetPublicSchema(publicSchema)
+	return registry
+}
+
+// PublicSchema implements [driver.PublicSchemaProvider].
+func (dialector Dialector) PublicSchema() string {
+	if dialector.options == nil {
+		return driver.PublicSchemaName()
+	}
+	return cmp.Or(dialector.options.PublicSchema, driver.PublicSchemaName())
+}
+
+// ModelRegistry implements [driver.ModelRegistryProvider].
+func (dialector *Dialector) ModelRegistry() *driver.ModelRegistry {
+	return dialector.registry
+}
+
 // RegisterModels registers the given models with the provided [gorm.DB] instance for multitenancy support.
-// Not safe for concurrent use by multiple goroutines.
-func RegisterModels(db *gorm.DB, models ...driver.TenantTabler) error {
-	return db.Dialector.(*Dialector).RegisterModels(models...)
+// Models either implement [driver.TenantTabler], or declare their tenancy with an embedded
+// [driver.SharedTable] or [driver.TenantTable], or a `gmt` struct tag (see [driver.ResolveModels]).
+// The relationships of the models are validated along with those of the models already registered
+// (see [driver.ValidateRelationships]).
+// Models are added to the models already registered. Safe for concurrent use by multiple goroutines.
+func RegisterModels(db *gorm.DB, models ...interface{}) error {
+	resolved, err := driver.ResolveModels(db, models...)
+	if err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to register models: %w", err))
+	}
+	return registerModels(db, resolved...)
+}
+
+// registerModels registers the resolved models with the provided [gorm.DB] instance.
+func registerModels(db *gorm.DB, models ...driver.TenantTabler) error {
+	validate := func(models []driver.TenantTabler) error {
+		return driver.ValidateRelationships(db, models...)
+	}
+	if err := db.Dialector.(*Dialector).registry.RegisterValidated(validate, models...); err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to register models: %w", err))
+	}
+	return nil
+}
+
+// UnregisterModels removes the given models from the models registered with the provided [gorm.DB] instance.
+// Models that cannot be registered are ignored. Safe for concurrent use by multiple goroutines.
+func UnregisterModels(db *gorm.DB, models ...interface{}) {
+	db.Dialector.(*Dialector).UnregisterModels(driver.ResolveRegistrableModels(db, models...)...)
+}
+
+// RegisterObjects registers the given SQL objects with the provided [gorm.DB] instance, to be
+// created alongside the registered models (see [driver.SQLObject]). Objects are added to the
+// objects already registered. Safe for concurrent use by multiple goroutines.
+func RegisterObjects(db *gorm.DB, objects ...driver.SQLObject) error {
+	return db.Dialector.(*Dialector).RegisterObjects(objects...)
 }
 
 // MigrateSharedModels migrates the public schema in the database.
@@ -129,7 +223,12 @@ func MigrateSharedModels(db *gorm.DB) error {
 }
 
 // MigrateTenantModels creates a new schema for a specific tenant in the MySQL database.
-func MigrateTenantModels(db *gorm.DB, tenantID string) error {
+// Tenants whose stored model fingerprint matches the registered models are skipped, unless
+// [gmtmigrator.WithForce] is provided.
+func MigrateTenantModels(db *gorm.DB, tenantID string, opts ...gmtmigrator.MigrateOption) error {
+	if len(opts) > 0 {
+		db = gmtmigrator.WithMigrateOptions(opts...)(db)
+	}
 	// MySQL advisory locks (GET_LOCK and RELEASE_LOCK) are connection-specific, meaning they
 	// are tied to the session that acquired them. If a different connection is used for
 	// releasing the lock, the operation will fail. Using db.Connection ensures that the
@@ -42,15 +42,41 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 }
 
 // MigrateTenantModels creates a database for a specific tenant and migrates the tenant tables.
+// The registered tenant SQL objects are dropped before, and recreated after, the tables are
+// migrated. As MySQL implicitly commits DDL statements, the objects are missing from the tenant
+// database while it is being migrated. The stored model fingerprint is checked again once the
+// advisory lock of the tenant is held, so that concurrent migrations of the same tenant migrate
+// it only once.
+//
+// MySQL implicitly commits DDL statements, so by default a migration that fails halfway leaves
+// the tenant database partially migrated. When [Options].SafeMigration is enabled, the definitions
+// of the tenant tables are instead copied into a shadow database, migrated and verified against
+// the registered models there. The tenant tables are then locked with LOCK TABLES ... WRITE while
+// their data is copied into the shadow tables, which are swapped into place with a single
+// multi-table RENAME TABLE, so that no write is lost. A failure before the swap leaves the tenant
+// database untouched. The SQL objects are dropped from the tenant database right before the copy,
+// as tables with triggers cannot be moved between databases, and recreated right after the swap;
+// the migration fails early if a tenant table has a trigger that is not a registered SQL object.
+// Safe mode requires MySQL 8.0.13 or later, which can rename tables locked with LOCK TABLES.
 func (m Migrator) MigrateTenantModels(tenantID string) (err error) {
 	m.logger.Printf("⏳ migrating tables for tenant %s", tenantID)
 
-	tenantModels := m.registry.TenantModels
+	tenantModels := m.registry.RegisteredTenantModels()
 	if len(tenantModels) == 0 {
 		m.logger.Printf("no tenant tables to migrate for tenant %q", tenantID)
 		return gmterrors.NewWithScheme(DriverName, errors.New("no tenant tables to migrate"))
 	}
 
+	plan, planErr := gmtmigrator.TenantPlanFromDB(m.DB, m.registry, tenantID)
+	if planErr != nil {
+		m.logger.Printf("failed to plan migration for tenant %q: %v", tenantID, planErr)
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to plan migration for tenant %q: %w", tenantID, planErr))
+	}
+	if plan.UpToDate {
+		m.logger.Printf("⏭️ private tables up to date for tenant %q, skipping", tenantID)
+		return nil
+	}
+
 	sqlstr := safe.QuoteRawSQLForTenant(m.DB, "CREATE DATABASE IF NOT EXISTS ", tenantID)
 	execErr := m.DB.Exec(sqlstr).Error
 	if execErr != nil {
@@ -71,6 +97,22 @@ func (m Migrator) MigrateTenantModels(tenantID string) (err error) {
 		}
 	}()
 
+	// Check the fingerprint again under the lock, as a concurrent migration may have migrated the
+	// tenant since.
+	applied, appliedErr := plan.Applied(m.DB)
+	if appliedErr != nil {
+		m.logger.Printf("failed to load model fingerprint for tenant %q: %v", tenantID, appliedErr)
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to load model fingerprint for tenant %q: %w", tenantID, appliedErr))
+	}
+	if applied {
+		m.logger.Printf("⏭️ private tables migrated concurrently for tenant %q, skipping", tenantID)
+		return nil
+	}
+
+	if m.options.SafeMigration {
+		return m.migrateTenantModelsSafe(tenantID, plan)
+	}
+
 	err = m.DB.Transaction(func(tx *gorm.DB) error {
 		reset, useDBErr := schema.UseDatabase(tx, tenantID)
 		if useDBErr != nil {
@@ -79,32 +121,46 @@ func (m Migrator) MigrateTenantModels(tenantID string) (err error) {
 		}
 		defer reset()
 
+		if dropErr := gmtmigrator.DropObjects(tx, plan.Objects); dropErr != nil {
+			m.logger.Printf("failed to drop SQL objects for tenant %q: %v", tenantID, dropErr)
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to drop SQL objects for tenant %q: %w", tenantID, dropErr))
+		}
 		if migrateErr := tx.
 			Scopes(gmtmigrator.WithOption(gmtmigrator.MigratorOption)).
-			AutoMigrate(driver.ModelsToInterfaces(tenantModels)...); migrateErr != nil {
+			AutoMigrate(driver.ModelsToInterfaces(plan.Models)...); migrateErr != nil {
 			m.logger.Printf("failed to migrate tables for tenant %q: %v", tenantID, err)
 			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to migrate tables for tenant %q: %w", tenantID, migrateErr))
 		}
+		if createErr := gmtmigrator.CreateObjects(tx, plan.Objects); createErr != nil {
+			m.logger.Printf("failed to create SQL objects for tenant %q: %v", tenantID, createErr)
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create SQL objects for tenant %q: %w", tenantID, createErr))
+		}
+		if saveErr := plan.Save(tx); saveErr != nil {
+			m.logger.Printf("failed to save model fingerprint for tenant %q: %v", tenantID, saveErr)
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to save model fingerprint for tenant %q: %w", tenantID, saveErr))
+		}
 		m.logger.Printf("✅ private tables migrated for tenant %q", tenantID)
 		return nil
 	})
 	return err
 }
 
-// MigrateSharedModels migrates the shared tables in the database.
+// MigrateSharedModels migrates the shared tables in the database, and recreates the registered
+// shared SQL objects in the public database.
 func (m Migrator) MigrateSharedModels() (err error) {
 	m.logger.Println("⏳ migrating public tables")
 
-	publicModels := m.registry.SharedModels
+	publicModels := m.registry.RegisteredSharedModels()
 	if len(publicModels) == 0 {
 		return gmterrors.NewWithScheme(DriverName, errors.New("no public tables to migrate"))
 	}
 
-	if err = m.DB.Exec("CREATE DATABASE IF NOT EXISTS public").Error; err != nil {
+	public := m.PublicSchema()
+	if err = m.DB.Exec(safe.QuoteRawSQLForTenant(m.DB, "CREATE DATABASE IF NOT EXISTS ", public)).Error; err != nil {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create public database: %w", err))
 	}
 
-	unlock, lockErr := m.acquireLock(m.DB, driver.PublicSchemaName())
+	unlock, lockErr := m.acquireLock(m.DB, public)
 	if lockErr != nil {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to acquire advisory lock for public schema: %w", lockErr))
 	}
@@ -130,11 +186,17 @@ func (m Migrator) MigrateSharedModels() (err error) {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to begin transaction: %w", err))
 	}
 
-	if err = tx.Exec("USE public").Error; err != nil {
+	if err = tx.Exec(safe.QuoteRawSQLForTenant(tx, "USE ", public)).Error; err != nil {
 		tx.Rollback()
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to switch to public database: %w", err))
 	}
 
+	objects := m.registry.SharedObjects()
+	if err = gmtmigrator.DropObjects(tx, objects); err != nil {
+		tx.Rollback()
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to drop shared SQL objects: %w", err))
+	}
+
 	if err = tx.
 		Scopes(gmtmigrator.WithOption(gmtmigrator.MigratorOption)).
 		AutoMigrate(driver.ModelsToInterfaces(publicModels)...); err != nil {
@@ -142,6 +204,11 @@ func (m Migrator) MigrateSharedModels() (err error) {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to migrate public tables: %w", err))
 	}
 
+	if err = gmtmigrator.CreateObjects(tx, objects); err != nil {
+		tx.Rollback()
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create shared SQL objects: %w", err))
+	}
+
 	return tx.Commit().Error
 }
 
@@ -155,6 +222,9 @@ func (m Migrator) DropDatabaseForTenant(tenantID string) (err error) {
 		if execErr := tx.Exec(sqlstr).Error; execErr != nil {
 			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to drop database for tenant %s: %w", tenantID, execErr))
 		}
+		if deleteErr := gmtmigrator.ForgetTenant(tx, tenantID); deleteErr != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to delete migration records for tenant %s: %w", tenantID, deleteErr))
+		}
 		m.logger.Printf("✅ database dropped for tenant %s", tenantID)
 		return nil
 	})
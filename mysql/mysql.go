@@ -46,13 +46,38 @@ in the DSN connection string of [Open]. The following options are available:
   - `gmt_retry_interval`: The initial interval between retry attempts. Default is 2 seconds.
   - `gmt_retry_max_interval`: The maximum interval between retry attempts. Default is 30 seconds.
 
+# Public Database
+
+Shared tables live in the database named by [driver.PublicSchemaName], "public" by default. To use a
+different database per dialector, set [Options].PublicSchema when calling [New], or specify
+`gmt_public_schema` in the DSN connection string of [Open]. The table names of shared models must
+be qualified with the configured database.
+
+# Safe Tenant Migrations
+
+MySQL implicitly commits DDL statements, so a tenant migration that fails halfway cannot be rolled
+back. To migrate tenants atomically, enable safe mode by setting [Options].SafeMigration when
+calling [New], or by specifying `gmt_safe_migration=true` in the DSN connection string of [Open].
+In safe mode the definitions of the tenant tables are copied into a shadow database, migrated and
+verified against the registered models. The tenant tables are then locked for writing while their
+data is copied into the shadow tables, which are swapped into place with a single multi-table
+RENAME TABLE statement before the locks are released. A failure before the swap leaves the tenant
+database untouched. Safe mode requires MySQL 8.0.13 or later.
+
+Safe mode copies all tenant data on every migration, and the tenant tables can neither be read nor
+written while the data is copied, so it is best suited to small or medium tenants. Tables with
+triggers can only be migrated if the triggers are registered SQL objects, which are recreated
+after the swap; the migration fails otherwise.
+
 # Shared Model Migrations
 
 To migrate shared models, use [MigrateSharedModels].
 
 # Tenant Model Migrations
 
-To migrate tenant-specific models, use [MigrateTenantModels].
+To migrate tenant-specific models, use [MigrateTenantModels]. Tenants whose stored model
+fingerprint matches the registered models are skipped; pass [migrator.WithForce] to migrate
+them regardless.
 
 # Tenant Offboarding
 
@@ -64,6 +89,7 @@ To configure the database for operations specific to a tenant, use [schema.UseDa
 
 [MySQL]: https://www.mysql.com
 [MySQL connection strings]: https://dev.mysql.com/doc/refman/8.4/en/connecting-using-uri-or-key-value-pairs.html#connecting-using-uri
+[migrator.WithForce]: https://pkg.go.dev/github.com/bartventer/gorm-multitenancy/v8/pkg/migrator#WithForce
 */
 package mysql
 
@@ -124,7 +150,7 @@ func (p *mysqlAdapter) OffboardTenant(_ context.Context, db *gorm.DB, tenantID s
 
 // RegisterModels implements [driver.DBFactory].
 func (p *mysqlAdapter) RegisterModels(_ context.Context, db *gorm.DB, models ...driver.TenantTabler) error {
-	return RegisterModels(db, models...)
+	return registerModels(db, models...)
 }
 
 // UseTenant implements [driver.DBFactory].
@@ -7,6 +7,10 @@ import (
 	"github.com/bartventer/gorm-multitenancy/mysql/v8/internal/testutil"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/drivertest"
+	gmtmigrator "github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
@@ -40,8 +44,79 @@ func newHarness[TB testing.TB](ctx context.Context, t TB) (drivertest.Harness, e
 	}, nil
 }
 
+func newSafeMigrationHarness[TB testing.TB](ctx context.Context, t TB) (drivertest.Harness, error) {
+	db := testutil.NewDB(t, ctx, func(dsn string) gorm.Dialector {
+		return New(Config{Config: mysql.Config{DSN: dsn}}, func(o *Options) {
+			o.SafeMigration = true
+		})
+	})
+	return &harness{
+		adapter: &mysqlAdapter{},
+		db:      db,
+	}, nil
+}
+
 var _ drivertest.Harness = new(harness)
 
 func TestMySQLConformance(t *testing.T) {
 	drivertest.RunConformanceTests(t, newHarness)
 }
+
+func TestMySQLConformanceSafeMigration(t *testing.T) {
+	drivertest.RunConformanceTests(t, newSafeMigrationHarness)
+}
+
+type safeTenant struct {
+	ID string `gorm:"primaryKey;size:63"`
+}
+
+func (safeTenant) TableName() string   { return "public.tenants" }
+func (safeTenant) IsSharedModel() bool { return true }
+
+type safeBook struct {
+	ID    uint
+	Title string `gorm:"size:255"`
+}
+
+func (safeBook) TableName() string   { return "books" }
+func (safeBook) IsSharedModel() bool { return false }
+
+func TestSafeMigrationTriggers(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t, ctx, func(dsn string) gorm.Dialector {
+		return New(Config{Config: mysql.Config{DSN: dsn}}, func(o *Options) {
+			o.SafeMigration = true
+		})
+	})
+	require.NoError(t, RegisterModels(db, &safeTenant{}, &safeBook{}))
+	require.NoError(t, MigrateSharedModels(db))
+	require.NoError(t, MigrateTenantModels(db, "tenant1"))
+	require.NoError(t, db.Exec("INSERT INTO `tenant1`.`books` (`title`) VALUES ('before')").Error)
+
+	trigger := driver.SQLObject{
+		Kind:      driver.SQLObjectTrigger,
+		Name:      "books_title_upper",
+		CreateSQL: "CREATE TRIGGER books_title_upper BEFORE INSERT ON books FOR EACH ROW SET NEW.title = UPPER(NEW.title)",
+		DropSQL:   "DROP TRIGGER IF EXISTS books_title_upper",
+	}
+	require.NoError(t, db.Exec("CREATE TRIGGER `tenant1`.`books_title_upper` BEFORE INSERT ON `tenant1`.`books` FOR EACH ROW SET NEW.title = UPPER(NEW.title)").Error)
+
+	t.Run("unregistered trigger", func(t *testing.T) {
+		err := MigrateTenantModels(db, "tenant1", gmtmigrator.WithForce())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "books_title_upper")
+	})
+
+	t.Run("registered trigger", func(t *testing.T) {
+		require.NoError(t, RegisterObjects(db, trigger))
+		require.NoError(t, MigrateTenantModels(db, "tenant1", gmtmigrator.WithForce()))
+
+		var titles []string
+		require.NoError(t, db.Raw("SELECT `title` FROM `tenant1`.`books` ORDER BY `id`").Scan(&titles).Error)
+		assert.Equal(t, []string{"before"}, titles, "the data of the tenant tables should be preserved")
+
+		var count int64
+		require.NoError(t, db.Raw("SELECT COUNT(*) FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = ? AND TRIGGER_NAME = ?", "tenant1", trigger.Name).Scan(&count).Error)
+		assert.Equal(t, int64(1), count, "the registered trigger should be recreated after the swap")
+	})
+}
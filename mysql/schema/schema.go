@@ -10,11 +10,13 @@ import (
 	"fmt"
 
 	"github.com/bartventer/gorm-multitenancy/mysql/v8/internal/safe"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
 	"gorm.io/gorm"
 )
 
 // UseDatabase sets the database for the given connection to the specified database name.
-// It returns a function that can be used to reset the database to the default value.
+// It returns a function that can be used to reset the database to the public database of the
+// connection's dialector (see [driver.PublicSchemaOf]).
 // This function does not perform any validation on the dbName parameter. It is the
 // responsibility of the caller to ensure that the dbName has been sanitized to avoid SQL
 // injection vulnerabilities.
@@ -29,7 +31,7 @@ import (
 //	if err != nil {
 //		// handle the error
 //	}
-//	defer reset() // reset the database to 'public'
+//	defer reset() // reset the database to the public database
 //	// ... do operations with the database with the database set to 'domain1'
 func UseDatabase(tx *gorm.DB, dbName string) (reset func() error, err error) {
 	if dbName == "" {
@@ -45,6 +47,7 @@ func UseDatabase(tx *gorm.DB, dbName string) (reset func() error, err error) {
 		return nil, err
 	}
 
-	reset = func() error { return tx.Exec("USE public").Error }
+	resetSQL := safe.QuoteRawSQLForTenant(tx, "USE ", driver.PublicSchemaOf(tx))
+	reset = func() error { return tx.Exec(resetSQL).Error }
 	return reset, nil
 }
@@ -0,0 +1,331 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"github.com/bartventer/gorm-multitenancy/mysql/v8/schema"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	gmtmigrator "github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
+	"gorm.io/gorm"
+)
+
+// shadowDatabaseNames returns the names of the shadow and backup databases used to migrate the
+// tenant in safe mode. The names are derived from a hash of the tenant identifier so that they
+// stay within MySQL's identifier length limit.
+func shadowDatabaseNames(tenantID string) (shadow, backup string) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(tenantID))
+	sum := h.Sum64()
+	return fmt.Sprintf("gmt_shadow_%016x", sum), fmt.Sprintf("gmt_backup_%016x", sum)
+}
+
+// quoteIdent quotes a (possibly qualified) identifier.
+func (m Migrator) quoteIdent(parts ...string) string {
+	sql := new(strings.Builder)
+	for i, part := range parts {
+		if i > 0 {
+			_ = sql.WriteByte('.')
+		}
+		m.DB.QuoteTo(sql, part)
+	}
+	return sql.String()
+}
+
+// migrateTenantModelsSafe migrates the tenant tables in safe mode.
+//
+// MySQL implicitly commits DDL statements, so a migration that fails halfway cannot be rolled back.
+// In safe mode the definitions of the tenant tables are copied into a shadow database, where the
+// registered models are migrated and verified. The tenant tables are then locked for writing, their
+// data is copied into the shadow tables, and the tables are swapped into place with a single,
+// atomic, multi-table RENAME TABLE statement, before the locks are released. As the tenant tables
+// are locked from the copy through the swap, no write can be lost. A failure before the swap leaves
+// the tenant database untouched.
+//
+// Tables with triggers cannot be moved between databases, so the migration fails early if a tenant
+// table has a trigger that is not a registered SQL object, which would be lost.
+//
+// The caller must hold the tenant's advisory lock and run on a single connection.
+func (m Migrator) migrateTenantModelsSafe(tenantID string, plan *gmtmigrator.TenantPlan) (err error) {
+	shadow, backup := shadowDatabaseNames(tenantID)
+
+	if triggerErr := m.checkTriggers(tenantID, plan.Objects); triggerErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("cannot migrate tenant %q in safe mode: %w", tenantID, triggerErr))
+	}
+
+	// Remove any leftovers from a previous, interrupted run.
+	for _, name := range []string{shadow, backup} {
+		if execErr := m.DB.Exec("DROP DATABASE IF EXISTS " + m.quoteIdent(name)).Error; execErr != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to drop stale database %q for tenant %q: %w", name, tenantID, execErr))
+		}
+	}
+	if execErr := m.DB.Exec("CREATE DATABASE " + m.quoteIdent(shadow)).Error; execErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create shadow database for tenant %q: %w", tenantID, execErr))
+	}
+	swapped := false
+	defer func() {
+		if swapped {
+			return
+		}
+		if dropErr := m.DB.Exec("DROP DATABASE IF EXISTS " + m.quoteIdent(shadow)).Error; dropErr != nil {
+			m.logger.Printf("failed to drop shadow database for tenant %q: %v", tenantID, dropErr)
+			err = errors.Join(err, fmt.Errorf("failed to drop shadow database for tenant %q: %w", tenantID, dropErr))
+		}
+	}()
+
+	tables, err := m.baseTables(tenantID)
+	if err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to list tables for tenant %q: %w", tenantID, err))
+	}
+	if err = m.copyTableDefinitions(tenantID, shadow, tables); err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to copy tables for tenant %q: %w", tenantID, err))
+	}
+
+	reset, useDBErr := schema.UseDatabase(m.DB, shadow)
+	if useDBErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to switch to shadow database for tenant %q: %w", tenantID, useDBErr))
+	}
+	defer reset()
+
+	if migrateErr := m.DB.
+		Scopes(gmtmigrator.WithOption(gmtmigrator.MigratorOption)).
+		AutoMigrate(driver.ModelsToInterfaces(plan.Models)...); migrateErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to migrate shadow tables for tenant %q: %w", tenantID, migrateErr))
+	}
+	if verifyErr := m.verifyModels(plan.Models); verifyErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to verify shadow tables for tenant %q: %w", tenantID, verifyErr))
+	}
+
+	// The shadow database now holds every table of the tenant database, plus any new ones.
+	shadowTables, err := m.baseTables(shadow)
+	if err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to list shadow tables for tenant %q: %w", tenantID, err))
+	}
+	resetTenant, useDBErr := schema.UseDatabase(m.DB, tenantID)
+	if useDBErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to switch to tenant database %q: %w", tenantID, useDBErr))
+	}
+	defer resetTenant()
+	if execErr := m.DB.Exec("CREATE DATABASE " + m.quoteIdent(backup)).Error; execErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create backup database for tenant %q: %w", tenantID, execErr))
+	}
+	defer func() {
+		if !swapped {
+			_ = m.DB.Exec("DROP DATABASE IF EXISTS " + m.quoteIdent(backup)).Error
+		}
+	}()
+	if dropErr := gmtmigrator.DropObjects(m.DB, plan.Objects); dropErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to drop SQL objects for tenant %q: %w", tenantID, dropErr))
+	}
+	defer func() {
+		if swapped {
+			return
+		}
+		// Restore the objects of the untouched tenant tables.
+		if createErr := gmtmigrator.CreateObjects(m.DB, plan.Objects); createErr != nil {
+			m.logger.Printf("failed to restore SQL objects for tenant %q: %v", tenantID, createErr)
+			err = errors.Join(err, fmt.Errorf("failed to restore SQL objects for tenant %q: %w", tenantID, createErr))
+		}
+	}()
+
+	if swapErr := m.copyAndSwap(tenantID, shadow, backup, tables, shadowTables); swapErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to swap shadow tables for tenant %q: %w", tenantID, swapErr))
+	}
+	swapped = true
+
+	if createErr := gmtmigrator.CreateObjects(m.DB, plan.Objects); createErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create SQL objects for tenant %q: %w", tenantID, createErr))
+	}
+	if saveErr := plan.Save(m.DB); saveErr != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to save model fingerprint for tenant %q: %w", tenantID, saveErr))
+	}
+
+	// The tenant has been migrated at this point; failing to clean up is not fatal, as leftovers
+	// are removed by the next run.
+	for _, name := range []string{backup, shadow} {
+		if dropErr := m.DB.Exec("DROP DATABASE IF EXISTS " + m.quoteIdent(name)).Error; dropErr != nil {
+			m.logger.Printf("failed to drop database %q for tenant %q: %v", name, tenantID, dropErr)
+		}
+	}
+	m.logger.Printf("✅ private tables migrated for tenant %q (safe mode)", tenantID)
+	return nil
+}
+
+// checkTriggers returns an error if a table in the given database has a trigger that is not one of
+// the given SQL objects.
+func (m Migrator) checkTriggers(database string, objects []driver.SQLObject) error {
+	var triggers []struct {
+		Name  string `gorm:"column:TRIGGER_NAME"`
+		Table string `gorm:"column:EVENT_OBJECT_TABLE"`
+	}
+	if err := m.queryRaw(
+		"SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = ? ORDER BY TRIGGER_NAME",
+		database,
+	).Scan(&triggers).Error; err != nil {
+		return fmt.Errorf("failed to list triggers: %w", err)
+	}
+	var errs []error
+	for _, trigger := range triggers {
+		registered := slices.ContainsFunc(objects, func(o driver.SQLObject) bool {
+			return o.Kind == driver.SQLObjectTrigger && o.Name == trigger.Name
+		})
+		if !registered {
+			errs = append(errs, fmt.Errorf("table %q has trigger %q, which is not a registered SQL object", trigger.Table, trigger.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// baseTables returns the names of the base tables in the given database.
+func (m Migrator) baseTables(database string) ([]string, error) {
+	var tables []string
+	err := m.queryRaw(
+		"SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = ? ORDER BY TABLE_NAME",
+		database, "BASE TABLE",
+	).Scan(&tables).Error
+	return tables, err
+}
+
+// copyTableDefinitions creates the given tables of one database, without their data, in another.
+// Foreign key checks are disabled for the duration of the copy so that the tables can be created
+// in any order.
+func (m Migrator) copyTableDefinitions(from, to string, tables []string) (err error) {
+	if len(tables) == 0 {
+		return nil
+	}
+	resetChecks, err := m.disableForeignKeyChecks()
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, resetChecks()) }()
+
+	reset, err := schema.UseDatabase(m.DB, to)
+	if err != nil {
+		return err
+	}
+	defer reset()
+
+	for _, table := range tables {
+		var name, createSQL string
+		// SHOW CREATE TABLE keeps foreign keys that reference tables in the same database
+		// unqualified, so they resolve to the copies in the target database.
+		if err = m.DB.Raw("SHOW CREATE TABLE "+m.quoteIdent(from, table)).Row().Scan(&name, &createSQL); err != nil {
+			return fmt.Errorf("failed to read definition of table %q: %w", table, err)
+		}
+		if err = m.DB.Exec(createSQL).Error; err != nil {
+			return fmt.Errorf("failed to create table %q: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// copyAndSwap locks the tables of the tenant and shadow databases for writing, copies the data of
+// the tenant tables into the shadow tables, and swaps the shadow tables into the tenant database,
+// moving the tenant tables into the backup database, before releasing the locks.
+func (m Migrator) copyAndSwap(tenantID, shadow, backup string, tables, shadowTables []string) (err error) {
+	locks := make([]string, 0, len(tables)+len(shadowTables))
+	for _, table := range tables {
+		locks = append(locks, m.quoteIdent(tenantID, table)+" WRITE")
+	}
+	for _, table := range shadowTables {
+		locks = append(locks, m.quoteIdent(shadow, table)+" WRITE")
+	}
+	if len(locks) == 0 {
+		return nil
+	}
+
+	resetChecks, err := m.disableForeignKeyChecks()
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, resetChecks()) }()
+
+	if err = m.DB.Exec("LOCK TABLES " + strings.Join(locks, ", ")).Error; err != nil {
+		return fmt.Errorf("failed to lock tables: %w", err)
+	}
+	defer func() {
+		if unlockErr := m.DB.Exec("UNLOCK TABLES").Error; unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to unlock tables: %w", unlockErr))
+		}
+	}()
+
+	for _, table := range tables {
+		if err = m.copyTableData(tenantID, shadow, table); err != nil {
+			return err
+		}
+	}
+
+	renames := make([]string, 0, len(tables)+len(shadowTables))
+	for _, table := range tables {
+		renames = append(renames, m.quoteIdent(tenantID, table)+" TO "+m.quoteIdent(backup, table))
+	}
+	for _, table := range shadowTables {
+		renames = append(renames, m.quoteIdent(shadow, table)+" TO "+m.quoteIdent(tenantID, table))
+	}
+	return m.DB.Exec("RENAME TABLE " + strings.Join(renames, ", ")).Error
+}
+
+// copyTableData copies the data of a table of one database into the table with the same name in
+// another, for the columns the tables have in common, except generated columns.
+func (m Migrator) copyTableData(from, to, table string) error {
+	var columns []string
+	if err := m.queryRaw(
+		`SELECT src.COLUMN_NAME FROM information_schema.COLUMNS src
+		JOIN information_schema.COLUMNS dst ON dst.TABLE_SCHEMA = ? AND dst.TABLE_NAME = src.TABLE_NAME AND dst.COLUMN_NAME = src.COLUMN_NAME
+		WHERE src.TABLE_SCHEMA = ? AND src.TABLE_NAME = ? AND src.EXTRA NOT LIKE ? AND dst.EXTRA NOT LIKE ?
+		ORDER BY src.ORDINAL_POSITION`,
+		to, from, table, "%GENERATED%", "%GENERATED%",
+	).Scan(&columns).Error; err != nil {
+		return fmt.Errorf("failed to read columns of table %q: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	for i, column := range columns {
+		columns[i] = m.quoteIdent(column)
+	}
+	list := strings.Join(columns, ", ")
+	if err := m.DB.Exec(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+		m.quoteIdent(to, table), list, list, m.quoteIdent(from, table),
+	)).Error; err != nil {
+		return fmt.Errorf("failed to copy data of table %q: %w", table, err)
+	}
+	return nil
+}
+
+// disableForeignKeyChecks disables foreign key checks for the session, so that tables can be
+// created and filled in any order, and returns a function enabling them again.
+func (m Migrator) disableForeignKeyChecks() (func() error, error) {
+	if err := m.DB.Exec("SET SESSION FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+		return nil, err
+	}
+	return func() error {
+		return m.DB.Exec("SET SESSION FOREIGN_KEY_CHECKS = 1").Error
+	}, nil
+}
+
+// verifyModels checks that the tables and columns of the given models exist in the current database.
+func (m Migrator) verifyModels(models []driver.TenantTabler) error {
+	for _, model := range driver.ModelsToInterfaces(models) {
+		stmt := &gorm.Statement{DB: m.DB}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		if !m.HasTable(model) {
+			return fmt.Errorf("table %q is missing", stmt.Schema.Table)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !m.HasColumn(model, field.DBName) {
+				return fmt.Errorf("column %q of table %q is missing", field.DBName, stmt.Schema.Table)
+			}
+		}
+	}
+	return nil
+}
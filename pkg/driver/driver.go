@@ -49,6 +49,40 @@ type (
 		CurrentTenant(ctx context.Context, db *gorm.DB) string
 	}
 
+	// ModelGrouper is an optional interface for tenant-specific models, assigning the model to a
+	// named model group. Tenants only receive the tables of the model groups they subscribe to.
+	// Tenant models that do not implement this interface belong to [DefaultModelGroup], to which
+	// every tenant subscribes.
+	//
+	// Example:
+	//
+	// 	type Report struct {
+	// 		gorm.Model
+	// 		Title string
+	// 	}
+	//
+	// 	func (Report) TableName() string   { return "reports" }
+	// 	func (Report) IsSharedModel() bool { return false }
+	// 	func (Report) ModelGroup() string  { return "analytics" }
+	ModelGrouper interface {
+		// ModelGroup returns the name of the model group the model belongs to.
+		ModelGroup() string
+	}
+
+	// ModelRegistryProvider is implemented by dialectors that keep a [ModelRegistry], exposing the
+	// models registered for multitenancy support. Not intended for direct use in application code.
+	ModelRegistryProvider interface {
+		// ModelRegistry returns the registry of models registered with the dialector.
+		ModelRegistry() *ModelRegistry
+	}
+
+	// PublicSchemaProvider is implemented by dialectors that configure the name of the public
+	// schema holding the tables of shared models. See [PublicSchemaOf].
+	PublicSchemaProvider interface {
+		// PublicSchema returns the name of the public schema.
+		PublicSchema() string
+	}
+
 	// TenantTabler defines an interface for models within a multi-tenant architecture,
 	// extending [schema.Tabler]. Models must define their table name and indicate if they
 	// are shared across tenants. Crucial for differentiating between shared and tenant-specific data.
@@ -0,0 +1,190 @@
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+)
+
+// SQLObjectKind identifies the kind of a [SQLObject].
+type SQLObjectKind string
+
+// Define values for [SQLObjectKind].
+const (
+	SQLObjectView     SQLObjectKind = "view"
+	SQLObjectFunction SQLObjectKind = "function"
+	SQLObjectTrigger  SQLObjectKind = "trigger"
+	SQLObjectType     SQLObjectKind = "type"
+)
+
+// SQLObject describes a raw database object, such as a view, function, trigger or enum type,
+// that is created alongside the registered models. Tenant objects are created in each tenant's
+// schema (database, for MySQL) by the tenant migration, and shared objects in the public schema
+// by the shared migration, with the target namespace selected as the current schema, so the SQL
+// should use unqualified names for objects in the target namespace.
+//
+// On migration, the objects are dropped in reverse dependency order with DropSQL, the models are
+// migrated, and the objects are then created in dependency order with CreateSQL. DropSQL must
+// therefore succeed if the object does not exist, e.g. DROP VIEW IF EXISTS.
+//
+// Example:
+//
+//	driver.SQLObject{
+//		Kind:      driver.SQLObjectView,
+//		Name:      "book_titles",
+//		CreateSQL: "CREATE VIEW book_titles AS SELECT id, title FROM books",
+//		DropSQL:   "DROP VIEW IF EXISTS book_titles",
+//	}
+type SQLObject struct {
+	Kind      SQLObjectKind // The kind of the object.
+	Name      string        // The unique name of the object.
+	Shared    bool          // Whether the object is created in the public schema, instead of per tenant.
+	CreateSQL string        // The SQL creating the object.
+	DropSQL   string        // The SQL dropping the object, if it exists; optional.
+	DependsOn []string      // The names of the objects this object depends on.
+}
+
+// Checksum returns a stable hash of the definition of the object.
+func (o SQLObject) Checksum() string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%t\x00", o.Kind, o.Name, o.Shared)
+	_, _ = io.WriteString(h, o.CreateSQL+"\x00"+o.DropSQL+"\x00")
+	for _, dep := range o.DependsOn {
+		_, _ = io.WriteString(h, dep+"\x00")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (o SQLObject) validate() error {
+	if o.Name == "" {
+		return errors.New("SQL object name is empty")
+	}
+	if o.CreateSQL == "" {
+		return fmt.Errorf("SQL object %q has no create SQL", o.Name)
+	}
+	if slices.Contains(o.DependsOn, o.Name) {
+		return fmt.Errorf("SQL object %q depends on itself", o.Name)
+	}
+	return nil
+}
+
+// RegisterObjects adds the SQL objects to the registry. An object replaces any registered object
+// with the same name, keeping its position. Dependencies must be registered before, or together
+// with, the objects that depend on them. If any object is invalid, or the objects depend on each
+// other in a cycle, an error is returned and none of the objects are added.
+func (r *ModelRegistry) RegisterObjects(objects ...SQLObject) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	merged := slices.Clone(r.objects)
+	var errs []error
+	for _, object := range objects {
+		if err := object.validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if i := slices.IndexFunc(merged, func(o SQLObject) bool { return o.Name == object.Name }); i >= 0 {
+			merged[i] = object
+		} else {
+			merged = append(merged, object)
+		}
+	}
+	if len(errs) == 0 {
+		for _, object := range merged {
+			for _, dep := range object.DependsOn {
+				if !slices.ContainsFunc(merged, func(o SQLObject) bool { return o.Name == dep }) {
+					errs = append(errs, fmt.Errorf("SQL object %q depends on unknown object %q", object.Name, dep))
+				}
+			}
+		}
+	}
+	if len(errs) == 0 {
+		if _, err := sortObjects(merged); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return gmterrors.New(errors.Join(errs...))
+	}
+	r.objects = merged
+	return nil
+}
+
+// UnregisterObjects removes the SQL objects with the given names from the registry. Objects that
+// are not registered are ignored.
+func (r *ModelRegistry) UnregisterObjects(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.objects = slices.DeleteFunc(r.objects, func(o SQLObject) bool { return slices.Contains(names, o.Name) })
+}
+
+// SharedObjects returns the registered shared SQL objects, in dependency order.
+func (r *ModelRegistry) SharedObjects() []SQLObject {
+	return r.objectsWhere(true)
+}
+
+// TenantObjects returns the registered tenant SQL objects, in dependency order.
+func (r *ModelRegistry) TenantObjects() []SQLObject {
+	return r.objectsWhere(false)
+}
+
+func (r *ModelRegistry) objectsWhere(shared bool) []SQLObject {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sorted, _ := sortObjects(r.objects) // cycles are rejected on registration
+	out := make([]SQLObject, 0, len(sorted))
+	for _, object := range sorted {
+		if object.Shared == shared {
+			out = append(out, object)
+		}
+	}
+	return out
+}
+
+// sortObjects returns the objects in dependency order, keeping the registration order of
+// independent objects. Dependencies on objects that are not registered are ignored. It returns an
+// error if the objects depend on each other in a cycle.
+func sortObjects(objects []SQLObject) ([]SQLObject, error) {
+	index := make(map[string]int, len(objects))
+	for i, object := range objects {
+		index[object.Name] = i
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(objects))
+	sorted := make([]SQLObject, 0, len(objects))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("circular dependency involving SQL object %q", objects[i].Name)
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		for _, dep := range objects[i].DependsOn {
+			if j, ok := index[dep]; ok {
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = visited
+		sorted = append(sorted, objects[i])
+		return nil
+	}
+	for i := range objects {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,83 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func objectNames(objects []SQLObject) []string {
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		names = append(names, object.Name)
+	}
+	return names
+}
+
+func TestModelRegistry_RegisterObjects(t *testing.T) {
+	t.Run("dependency order", func(t *testing.T) {
+		r := &ModelRegistry{}
+		err := r.RegisterObjects(
+			SQLObject{Name: "c", CreateSQL: "c", DependsOn: []string{"b"}},
+			SQLObject{Name: "a", CreateSQL: "a"},
+			SQLObject{Name: "b", CreateSQL: "b", DependsOn: []string{"a"}},
+			SQLObject{Name: "s", CreateSQL: "s", Shared: true},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, objectNames(r.TenantObjects()))
+		assert.Equal(t, []string{"s"}, objectNames(r.SharedObjects()))
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		r := &ModelRegistry{}
+		require.NoError(t, r.RegisterObjects(SQLObject{Name: "a", CreateSQL: "v1"}, SQLObject{Name: "b", CreateSQL: "b"}))
+		require.NoError(t, r.RegisterObjects(SQLObject{Name: "a", CreateSQL: "v2"}))
+		objects := r.TenantObjects()
+		require.Len(t, objects, 2)
+		assert.Equal(t, "v2", objects[0].CreateSQL)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			objects []SQLObject
+		}{
+			{"empty name", []SQLObject{{CreateSQL: "a"}}},
+			{"empty create SQL", []SQLObject{{Name: "a"}}},
+			{"self dependency", []SQLObject{{Name: "a", CreateSQL: "a", DependsOn: []string{"a"}}}},
+			{"unknown dependency", []SQLObject{{Name: "a", CreateSQL: "a", DependsOn: []string{"missing"}}}},
+			{"cycle", []SQLObject{
+				{Name: "a", CreateSQL: "a", DependsOn: []string{"b"}},
+				{Name: "b", CreateSQL: "b", DependsOn: []string{"a"}},
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				r := &ModelRegistry{}
+				require.NoError(t, r.RegisterObjects(SQLObject{Name: "existing", CreateSQL: "x"}))
+				assert.Error(t, r.RegisterObjects(tt.objects...))
+				assert.Equal(t, []string{"existing"}, objectNames(r.TenantObjects()), "registry should be unchanged")
+			})
+		}
+	})
+
+	t.Run("unregister", func(t *testing.T) {
+		r := &ModelRegistry{}
+		require.NoError(t, r.RegisterObjects(SQLObject{Name: "a", CreateSQL: "a"}, SQLObject{Name: "b", CreateSQL: "b"}))
+		r.UnregisterObjects("a", "unknown")
+		assert.Equal(t, []string{"b"}, objectNames(r.TenantObjects()))
+		assert.Len(t, r.Snapshot().Objects, 1)
+	})
+}
+
+func TestSQLObject_Checksum(t *testing.T) {
+	a := SQLObject{Kind: SQLObjectView, Name: "v", CreateSQL: "CREATE VIEW v AS SELECT 1"}
+	b := a
+	assert.Equal(t, a.Checksum(), b.Checksum())
+	b.CreateSQL = "CREATE VIEW v AS SELECT 2"
+	assert.NotEqual(t, a.Checksum(), b.Checksum())
+	b = a
+	b.Shared = true
+	assert.NotEqual(t, a.Checksum(), b.Checksum())
+}
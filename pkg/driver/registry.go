@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	"gorm.io/gorm"
 )
 
 // PublicSchemaEnvVar is the environment variable that contains the name of the public schema.
@@ -15,17 +19,62 @@ const PublicSchemaEnvVar = "GMT_PUBLIC_SCHEMA_NAME"
 
 // PublicSchemaName returns the name of the public schema as defined by the [PublicSchemaEnvVar]
 // environment variable, defaulting to "public" if the variable is not set. This schema name is
-// used to identify shared models.
+// used to identify shared models, unless configured per dialector (see [PublicSchemaOf]).
 func PublicSchemaName() string {
 	return cmp.Or(os.Getenv(PublicSchemaEnvVar), "public")
 }
 
+// PublicSchemaOf returns the name of the public schema for the db, as configured on its dialector
+// if the dialector implements [PublicSchemaProvider], defaulting to [PublicSchemaName].
+func PublicSchemaOf(db *gorm.DB) string {
+	if db != nil && db.Config != nil {
+		if provider, ok := db.Dialector.(PublicSchemaProvider); ok {
+			if name := provider.PublicSchema(); name != "" {
+				return name
+			}
+		}
+	}
+	return PublicSchemaName()
+}
+
 type (
 	// ModelRegistry holds the models registered for multitenancy support, categorizing them into
-	// shared and tenant-specific models. Not intended for direct use in application code.
+	// shared and tenant-specific models. Models are identified by their table name. The zero value
+	// is an empty registry ready for use. Safe for concurrent use by multiple goroutines.
+	// Not intended for direct use in application code.
 	ModelRegistry struct {
-		SharedModels []TenantTabler // SharedModels contains the models that are shared across tenants.
-		TenantModels []TenantTabler // TenantModels contains the models that are specific to a tenant.
+		// SharedModels contains the models that are shared across tenants.
+		//
+		// Deprecated: Reading the field directly is not safe for concurrent use with
+		// registration. Use [ModelRegistry.RegisteredSharedModels] instead.
+		SharedModels []TenantTabler
+
+		// TenantModels contains the models that are specific to a tenant.
+		//
+		// Deprecated: Reading the field directly is not safe for concurrent use with
+		// registration. Use [ModelRegistry.RegisteredTenantModels] instead.
+		TenantModels []TenantTabler
+
+		mu           sync.RWMutex
+		publicSchema string        // Name of the public schema; defaults to [PublicSchemaName].
+		objects      []SQLObject   // Raw database objects, in registration order.
+		seeders      []NamedSeeder // Tenant seeders, in registration order.
+
+		declaredShared map[reflect.Type]string // Shared models that declare their tenancy, by type.
+	}
+
+	// RegisteredModel describes a model registered for multitenancy support.
+	RegisteredModel struct {
+		Model     interface{} // The registered model, as provided at registration.
+		TableName string      // The table name of the model.
+	}
+
+	// ModelSnapshot is a point-in-time copy of the models registered for multitenancy support,
+	// in registration order. It is not affected by subsequent registrations.
+	ModelSnapshot struct {
+		Shared  []RegisteredModel // Models that are shared across tenants.
+		Tenant  []RegisteredModel // Models that are specific to a tenant.
+		Objects []SQLObject       // Raw database objects, in registration order.
 	}
 )
 
@@ -33,36 +82,200 @@ type (
 // shared and tenant-specific based on their characteristics. It returns an error if any model fails validation.
 // Not intended for direct use in application code.
 func NewModelRegistry(models ...TenantTabler) (*ModelRegistry, error) {
-	var (
-		registry = &ModelRegistry{
-			SharedModels: make([]TenantTabler, 0, len(models)),
-			TenantModels: make([]TenantTabler, 0, len(models)),
-		}
-		errs []error
-	)
+	registry := &ModelRegistry{}
+	if err := registry.Register(models...); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// SetPublicSchema sets the name of the public schema used to validate the table names of shared
+// models. An empty name resets it to [PublicSchemaName].
+func (r *ModelRegistry) SetPublicSchema(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.publicSchema = name
+}
 
+// PublicSchema returns the name of the public schema used to validate the table names of shared models.
+func (r *ModelRegistry) PublicSchema() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return cmp.Or(r.publicSchema, PublicSchemaName())
+}
+
+// Register adds the models to the registry. A model replaces any registered model with the same
+// table name, keeping its position; other models are appended. If any model fails validation,
+// an error is returned and none of the models are added.
+func (r *ModelRegistry) Register(models ...TenantTabler) error {
+	return r.RegisterValidated(nil, models...)
+}
+
+// RegisterValidated is like [ModelRegistry.Register], but also calls validate, if not nil, with
+// the registered models followed by the given models. The registry is locked from validation
+// until the models are added, so that validate sees every model registered before them; validate
+// must not call methods of the registry. If validate returns an error, none of the models are added.
+func (r *ModelRegistry) RegisterValidated(validate func(models []TenantTabler) error, models ...TenantTabler) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	public := cmp.Or(r.publicSchema, PublicSchemaName())
+	var errs []error
 	for _, model := range models {
 		tableName := model.TableName()
 		if model.IsSharedModel() {
-			if err := validateSharedModel(tableName); err != nil {
+			if err := validateSharedModel(tableName, public); err != nil {
 				errs = append(errs, err)
-				continue
 			}
-			registry.SharedModels = append(registry.SharedModels, model)
+		} else if err := validateTenantModel(tableName); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if validate != nil {
+		if err := validate(slices.Concat(r.SharedModels, r.TenantModels, models)); err != nil {
+			return err
+		}
+	}
+
+	for _, model := range models {
+		// Drop the model from the other category, in case it changed.
+		if model.IsSharedModel() {
+			r.TenantModels = removeModel(r.TenantModels, model.TableName())
+			r.SharedModels = upsertModel(r.SharedModels, model)
 		} else {
-			if err := validateTenantModel(tableName); err != nil {
-				errs = append(errs, err)
-				continue
-			}
-			registry.TenantModels = append(registry.TenantModels, model)
+			r.SharedModels = removeModel(r.SharedModels, model.TableName())
+			r.TenantModels = upsertModel(r.TenantModels, model)
 		}
 	}
+	r.indexDeclaredShared()
+	return nil
+}
 
-	if len(errs) > 0 {
-		return nil, errors.Join(errs...)
+// Unregister removes the models with the same table names as the given models from the registry.
+// Models that are not registered are ignored.
+func (r *ModelRegistry) Unregister(models ...TenantTabler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, model := range models {
+		tableName := model.TableName()
+		r.SharedModels = removeModel(r.SharedModels, tableName)
+		r.TenantModels = removeModel(r.TenantModels, tableName)
 	}
+	r.indexDeclaredShared()
+}
 
-	return registry, nil
+// DeclaredSharedTable returns the table name of the registered shared model of the given struct
+// type, if the model declares its tenancy rather than implementing [TenantTabler] (see [ResolveModels]).
+func (r *ModelRegistry) DeclaredSharedTable(typ reflect.Type) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tableName, ok := r.declaredShared[typ]
+	return tableName, ok
+}
+
+// indexDeclaredShared rebuilds the index of the registered shared models that declare their
+// tenancy. The registry must be locked for writing.
+func (r *ModelRegistry) indexDeclaredShared() {
+	clear(r.declaredShared)
+	for _, model := range r.SharedModels {
+		declared, ok := model.(*declaredModel)
+		if !ok {
+			continue
+		}
+		if r.declaredShared == nil {
+			r.declaredShared = make(map[reflect.Type]string)
+		}
+		r.declaredShared[modelType(declared.model)] = declared.tableName
+	}
+}
+
+// RegisteredSharedModels returns a copy of the registered shared models, in registration order.
+func (r *ModelRegistry) RegisteredSharedModels() []TenantTabler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.SharedModels)
+}
+
+// RegisteredTenantModels returns a copy of the registered tenant-specific models, in registration order.
+func (r *ModelRegistry) RegisteredTenantModels() []TenantTabler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.TenantModels)
+}
+
+// Snapshot returns a point-in-time copy of the registered models.
+func (r *ModelRegistry) Snapshot() ModelSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	describe := func(models []TenantTabler) []RegisteredModel {
+		out := make([]RegisteredModel, len(models))
+		for i, model := range models {
+			out[i] = RegisteredModel{Model: UnwrapModel(model), TableName: model.TableName()}
+		}
+		return out
+	}
+	return ModelSnapshot{
+		Shared:  describe(r.SharedModels),
+		Tenant:  describe(r.TenantModels),
+		Objects: slices.Clone(r.objects),
+	}
+}
+
+// upsertModel replaces the model with the same table name in models, or appends it.
+func upsertModel(models []TenantTabler, model TenantTabler) []TenantTabler {
+	tableName := model.TableName()
+	if i := slices.IndexFunc(models, func(m TenantTabler) bool { return m.TableName() == tableName }); i >= 0 {
+		models[i] = model
+		return models
+	}
+	return append(models, model)
+}
+
+// removeModel removes the model with the given table name from models.
+func removeModel(models []TenantTabler, tableName string) []TenantTabler {
+	return slices.DeleteFunc(models, func(m TenantTabler) bool { return m.TableName() == tableName })
+}
+
+// DefaultModelGroup is the model group of tenant models that do not implement [ModelGrouper].
+// Every tenant subscribes to this group.
+const DefaultModelGroup = "default"
+
+// ModelGroupOf returns the name of the model group the model belongs to.
+func ModelGroupOf(model TenantTabler) string {
+	if grouper, ok := UnwrapModel(model).(ModelGrouper); ok {
+		return cmp.Or(grouper.ModelGroup(), DefaultModelGroup)
+	}
+	return DefaultModelGroup
+}
+
+// TenantModelGroups returns the sorted names of the model groups of the registered tenant models.
+func (r *ModelRegistry) TenantModelGroups() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	groups := make([]string, 0, 1)
+	for _, model := range r.TenantModels {
+		if group := ModelGroupOf(model); !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// TenantModelsByGroup returns the registered tenant models that belong to any of the given
+// model groups, in registration order.
+func (r *ModelRegistry) TenantModelsByGroup(groups ...string) []TenantTabler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	models := make([]TenantTabler, 0, len(r.TenantModels))
+	for _, model := range r.TenantModels {
+		if slices.Contains(groups, ModelGroupOf(model)) {
+			models = append(models, model)
+		}
+	}
+	return models
 }
 
 // splitTableName splits a table name into its constituent parts, typically schema and table name.
@@ -75,11 +288,10 @@ func splitTableName(tableName string) []string {
 //
 // Example:
 //
-//	validateSharedModel("public.users") // returns nil
-//	validateSharedModel("users") // returns error
-func validateSharedModel(tableName string) error {
+//	validateSharedModel("public.users", "public") // returns nil
+//	validateSharedModel("users", "public") // returns error
+func validateSharedModel(tableName, public string) error {
 	parts := splitTableName(tableName)
-	public := PublicSchemaName()
 	if len(parts) != 2 || parts[0] != public {
 		return gmterrors.New(fmt.Errorf("invalid table name for model labeled as public table, table name should start with '%s.', got '%s'", public, tableName))
 	}
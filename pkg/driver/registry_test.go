@@ -1,10 +1,14 @@
 package driver
 
 import (
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
 )
 
 // Mock models for testing.
@@ -18,6 +22,17 @@ type tenantModel struct{}
 func (tenantModel) TableName() string   { return "tenant_specific" }
 func (tenantModel) IsSharedModel() bool { return false }
 
+type groupedTenantModel struct{}
+
+func (groupedTenantModel) TableName() string   { return "grouped" }
+func (groupedTenantModel) IsSharedModel() bool { return false }
+func (groupedTenantModel) ModelGroup() string  { return "premium" }
+
+type billingModel struct{}
+
+func (billingModel) TableName() string   { return "billing.invoices" }
+func (billingModel) IsSharedModel() bool { return true }
+
 type invalidSharedModel struct{}
 
 func (invalidSharedModel) TableName() string   { return "shared" }
@@ -29,8 +44,9 @@ func (invalidTenantModel) TableName() string   { return "public.tenant_specific"
 func (invalidTenantModel) IsSharedModel() bool { return false }
 
 func TestValidateSharedModel(t *testing.T) {
-	require.NoError(t, validateSharedModel("public.shared"), "valid shared model should not return an error")
-	assert.Error(t, validateSharedModel("shared"), "invalid shared model should return an error")
+	require.NoError(t, validateSharedModel("public.shared", "public"), "valid shared model should not return an error")
+	assert.Error(t, validateSharedModel("shared", "public"), "invalid shared model should return an error")
+	assert.Error(t, validateSharedModel("public.shared", "billing"), "shared model in another schema should return an error")
 }
 
 func TestValidateTenantModel(t *testing.T) {
@@ -42,8 +58,8 @@ func TestNewModelRegistry(t *testing.T) {
 	t.Run("valid models", func(t *testing.T) {
 		config, err := NewModelRegistry([]TenantTabler{sharedModel{}, tenantModel{}}...)
 		require.NoError(t, err)
-		assert.Len(t, config.SharedModels, 1, "there should be one shared model")
-		assert.Len(t, config.TenantModels, 1, "there should be one tenant model")
+		assert.Len(t, config.RegisteredSharedModels(), 1, "there should be one shared model")
+		assert.Len(t, config.RegisteredTenantModels(), 1, "there should be one tenant model")
 	})
 
 	t.Run("invalid table names", func(t *testing.T) {
@@ -51,3 +67,121 @@ func TestNewModelRegistry(t *testing.T) {
 		require.Error(t, err, "invalid table names should return an error")
 	})
 }
+
+func TestModelRegistry_TenantModelGroups(t *testing.T) {
+	registry, err := NewModelRegistry(sharedModel{}, tenantModel{}, groupedTenantModel{})
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultModelGroup, ModelGroupOf(tenantModel{}))
+	assert.Equal(t, "premium", ModelGroupOf(groupedTenantModel{}))
+	assert.Equal(t, []string{DefaultModelGroup, "premium"}, registry.TenantModelGroups())
+	assert.Equal(t, []TenantTabler{tenantModel{}}, registry.TenantModelsByGroup(DefaultModelGroup))
+	assert.Equal(t, []TenantTabler{groupedTenantModel{}}, registry.TenantModelsByGroup("premium"))
+	assert.Len(t, registry.TenantModelsByGroup(DefaultModelGroup, "premium"), 2)
+	assert.Empty(t, registry.TenantModelsByGroup("unknown"))
+}
+
+func TestModelRegistry_Register(t *testing.T) {
+	t.Run("additive", func(t *testing.T) {
+		var registry ModelRegistry
+		require.NoError(t, registry.Register(sharedModel{}))
+		require.NoError(t, registry.Register(tenantModel{}, groupedTenantModel{}))
+		assert.Equal(t, []TenantTabler{sharedModel{}}, registry.RegisteredSharedModels())
+		assert.Equal(t, []TenantTabler{tenantModel{}, groupedTenantModel{}}, registry.RegisteredTenantModels())
+	})
+
+	t.Run("replaces by table name", func(t *testing.T) {
+		var registry ModelRegistry
+		require.NoError(t, registry.Register(tenantModel{}, groupedTenantModel{}))
+		require.NoError(t, registry.Register(&tenantModel{}))
+		assert.Equal(t, []TenantTabler{&tenantModel{}, groupedTenantModel{}}, registry.RegisteredTenantModels())
+	})
+
+	t.Run("invalid models are not added", func(t *testing.T) {
+		var registry ModelRegistry
+		require.Error(t, registry.Register(tenantModel{}, invalidTenantModel{}))
+		assert.Empty(t, registry.RegisteredTenantModels())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var registry ModelRegistry
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, registry.Register(sharedModel{}, tenantModel{}, groupedTenantModel{}))
+				_ = registry.Snapshot()
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, registry.RegisteredSharedModels(), 1)
+		assert.Len(t, registry.RegisteredTenantModels(), 2)
+	})
+}
+
+func TestModelRegistry_RegisterValidated(t *testing.T) {
+	var registry ModelRegistry
+	require.NoError(t, registry.Register(sharedModel{}))
+
+	var validated []TenantTabler
+	validate := func(models []TenantTabler) error {
+		validated = models
+		return nil
+	}
+	require.NoError(t, registry.RegisterValidated(validate, tenantModel{}))
+	assert.Equal(t, []TenantTabler{sharedModel{}, tenantModel{}}, validated, "expected the registered models followed by the given models")
+
+	err := registry.RegisterValidated(func([]TenantTabler) error { return errors.New("invalid") }, groupedTenantModel{})
+	require.Error(t, err)
+	assert.Equal(t, []TenantTabler{tenantModel{}}, registry.RegisteredTenantModels(), "expected models failing validation not to be added")
+}
+
+func TestModelRegistry_Unregister(t *testing.T) {
+	registry, err := NewModelRegistry(sharedModel{}, tenantModel{}, groupedTenantModel{})
+	require.NoError(t, err)
+	registry.Unregister(tenantModel{}, invalidSharedModel{})
+	assert.Equal(t, []TenantTabler{sharedModel{}}, registry.RegisteredSharedModels())
+	assert.Equal(t, []TenantTabler{groupedTenantModel{}}, registry.RegisteredTenantModels())
+}
+
+func TestModelRegistry_Snapshot(t *testing.T) {
+	registry, err := NewModelRegistry(sharedModel{}, tenantModel{})
+	require.NoError(t, err)
+	snapshot := registry.Snapshot()
+	require.NoError(t, registry.Register(groupedTenantModel{}))
+
+	assert.Equal(t, ModelSnapshot{
+		Shared: []RegisteredModel{{Model: sharedModel{}, TableName: "public.shared"}},
+		Tenant: []RegisteredModel{{Model: tenantModel{}, TableName: "tenant_specific"}},
+	}, snapshot)
+	assert.Len(t, registry.Snapshot().Tenant, 2)
+}
+
+type publicSchemaDialector struct {
+	tests.DummyDialector
+	schema string
+}
+
+func (d publicSchemaDialector) PublicSchema() string { return d.schema }
+
+func TestPublicSchemaOf(t *testing.T) {
+	t.Setenv(PublicSchemaEnvVar, "")
+
+	db, err := gorm.Open(publicSchemaDialector{schema: "billing"}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, "billing", PublicSchemaOf(db))
+
+	db, err = gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, "public", PublicSchemaOf(db), "expected the default public schema")
+	assert.Equal(t, "public", PublicSchemaOf(&gorm.DB{}), "expected the default public schema for an uninitialized db")
+}
+
+func TestModelRegistry_SetPublicSchema(t *testing.T) {
+	var registry ModelRegistry
+	registry.SetPublicSchema("billing")
+	assert.Equal(t, "billing", registry.PublicSchema())
+	require.Error(t, registry.Register(sharedModel{}), "expected shared models outside the public schema to be rejected")
+	require.NoError(t, registry.Register(billingModel{}))
+}
@@ -0,0 +1,151 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ValidateRelationships parses the models with GORM's schema parser and validates their
+// relationships. It returns an error if:
+//
+//   - a shared model belongs to, or has a many-to-many relationship with, a tenant-specific
+//     model, as the foreign key or join table would live in the public schema and reference a
+//     table that only exists in tenant schemas; the error names the offending field.
+//   - a tenant-specific model belongs to, or has a many-to-many relationship with, a shared model
+//     that declares its tenancy without defining a TableName method, as GORM would reference the
+//     unqualified table name of the shared model from the tenant schema (see [ResolveModels]).
+//   - models reference each other in a cycle of belongs-to relationships, which prevents their
+//     tables from being created in dependency order. Self-references are allowed.
+//
+// Models are identified by table name; later models replace earlier ones with the same table name.
+// Related models are identified by type, so that they are resolved to their registered table
+// names. The models are parsed with a cache of their own, rather than the schema cache of the db.
+// Not intended for direct use in application code.
+func ValidateRelationships(db *gorm.DB, models ...TenantTabler) error {
+	schemas := make(map[string]*schema.Schema, len(models))
+	shared := make(map[string]bool, len(models))
+	byType := make(map[reflect.Type]TenantTabler, len(models))
+	cache := &sync.Map{}
+	for _, model := range models {
+		s, err := schema.Parse(UnwrapModel(model), cache, db.NamingStrategy)
+		if err != nil {
+			return gmterrors.New(fmt.Errorf("failed to parse model %T: %w", UnwrapModel(model), err))
+		}
+		schemas[model.TableName()] = s
+		shared[model.TableName()] = model.IsSharedModel()
+		byType[s.ModelType] = model
+	}
+	tables := slices.Sorted(maps.Keys(schemas))
+	public := PublicSchemaOf(db)
+
+	var errs []error
+	graph := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		s := schemas[table]
+		for _, name := range slices.Sorted(maps.Keys(s.Relationships.Relations)) {
+			rel := s.Relationships.Relations[name]
+			target := rel.FieldSchema.Table
+			related, registered := byType[rel.FieldSchema.ModelType]
+			if registered {
+				target = related.TableName()
+			}
+			switch rel.Type {
+			case schema.BelongsTo:
+				if target != table {
+					graph[table] = append(graph[table], target)
+				}
+			case schema.Many2Many:
+			default:
+				continue
+			}
+			if shared[table] && !isSharedTable(target, shared, public) {
+				errs = append(errs, fmt.Errorf(
+					"shared model %s (table %q) must not reference tenant model %s (table %q): field %s is a %s relationship",
+					s.Name, table, rel.FieldSchema.Name, target, rel.Field.Name, relationshipName(rel.Type),
+				))
+			}
+			if !shared[table] && registered && related.IsSharedModel() && rel.FieldSchema.Table != target {
+				errs = append(errs, fmt.Errorf(
+					"tenant model %s (table %q) must not reference shared model %s (table %q) unless it defines a TableName method returning the qualified table name: field %s is a %s relationship",
+					s.Name, table, rel.FieldSchema.Name, target, rel.Field.Name, relationshipName(rel.Type),
+				))
+			}
+		}
+	}
+	if cycle := findCycle(tables, graph); cycle != nil {
+		errs = append(errs, fmt.Errorf("circular reference between models: %s", strings.Join(cycle, " -> ")))
+	}
+	if len(errs) > 0 {
+		return gmterrors.New(errors.Join(errs...))
+	}
+	return nil
+}
+
+// isSharedTable reports whether the table belongs to a shared model. Tables of models that are
+// not being validated are considered shared if they are qualified with the public schema.
+func isSharedTable(table string, shared map[string]bool, public string) bool {
+	if isShared, ok := shared[table]; ok {
+		return isShared
+	}
+	return validateSharedModel(table, public) == nil
+}
+
+// relationshipName returns a human-readable name for the relationship type.
+func relationshipName(typ schema.RelationshipType) string {
+	switch typ {
+	case schema.BelongsTo:
+		return "belongs-to"
+	case schema.Many2Many:
+		return "many-to-many"
+	default:
+		return string(typ)
+	}
+}
+
+// findCycle returns the tables of the first cycle found in the graph, starting and ending with the
+// same table, or nil if the graph is acyclic. Tables are visited in the given order.
+func findCycle(tables []string, graph map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(tables))
+	var path []string
+	var visit func(table string) []string
+	visit = func(table string) []string {
+		state[table] = visiting
+		path = append(path, table)
+		for _, next := range graph[table] {
+			switch state[next] {
+			case visiting:
+				start := slices.Index(path, next)
+				return append(slices.Clone(path[start:]), next)
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[table] = visited
+		return nil
+	}
+	for _, table := range tables {
+		if state[table] == unvisited {
+			if cycle := visit(table); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type relOrg struct {
+	ID       uint
+	ParentID *uint
+	Parent   *relOrg // self-reference
+}
+
+func (relOrg) TableName() string   { return "public.orgs" }
+func (relOrg) IsSharedModel() bool { return true }
+
+type relProject struct {
+	ID    uint
+	OrgID uint
+	Org   relOrg
+}
+
+func (relProject) TableName() string   { return "projects" }
+func (relProject) IsSharedModel() bool { return false }
+
+type relPlan struct {
+	ID               uint
+	DefaultProjectID uint
+	DefaultProject   relProject
+}
+
+func (relPlan) TableName() string   { return "public.plans" }
+func (relPlan) IsSharedModel() bool { return true }
+
+type relTag struct {
+	ID       uint
+	Projects []relProject `gorm:"many2many:tag_projects"`
+}
+
+func (relTag) TableName() string   { return "public.tags" }
+func (relTag) IsSharedModel() bool { return true }
+
+type relTask struct {
+	ID     uint
+	StepID uint
+	Step   *relStep
+}
+
+func (relTask) TableName() string   { return "tasks" }
+func (relTask) IsSharedModel() bool { return false }
+
+type relStep struct {
+	ID     uint
+	TaskID uint
+	Task   *relTask
+}
+
+func (relStep) TableName() string   { return "steps" }
+func (relStep) IsSharedModel() bool { return false }
+
+type relAuthor struct {
+	SharedTable
+	ID uint
+}
+
+type relChapter struct {
+	ID          uint
+	RelAuthorID uint
+	RelAuthor   relAuthor
+}
+
+func (relChapter) TableName() string   { return "chapters" }
+func (relChapter) IsSharedModel() bool { return false }
+
+func TestValidateRelationships(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, ValidateRelationships(db, relOrg{}, relProject{}))
+	})
+
+	t.Run("shared belongs to tenant", func(t *testing.T) {
+		err := ValidateRelationships(db, relProject{}, relPlan{})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "field DefaultProject is a belongs-to relationship")
+	})
+
+	t.Run("shared many to many tenant", func(t *testing.T) {
+		err := ValidateRelationships(db, relProject{}, relTag{})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "field Projects is a many-to-many relationship")
+	})
+
+	t.Run("circular reference", func(t *testing.T) {
+		err := ValidateRelationships(db, relTask{}, relStep{})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "circular reference between models: steps -> tasks -> steps")
+	})
+
+	t.Run("tenant belongs to declared shared", func(t *testing.T) {
+		resolved, err := ResolveModels(db, &relAuthor{})
+		require.NoError(t, err)
+		assert.NoError(t, ValidateRelationships(db, relChapter{}), "expected unregistered related models to be ignored")
+
+		err = ValidateRelationships(db, append(resolved, relChapter{})...)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "field RelAuthor is a belongs-to relationship")
+	})
+}
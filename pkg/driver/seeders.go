@@ -0,0 +1,44 @@
+package driver
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+)
+
+// NamedSeeder is a seeder as stored in the [ModelRegistry]. Seeders are identified by name and run
+// by the multitenancy package, which defines the seeding method.
+type NamedSeeder interface {
+	// Name returns the unique name of the seeder.
+	Name() string
+}
+
+// RegisterSeeders adds the seeders to the registry, in order. A seeder replaces any registered
+// seeder with the same name, keeping its position. If any seeder has an empty name, an error is
+// returned and none of the seeders are added.
+func (r *ModelRegistry) RegisterSeeders(seeders ...NamedSeeder) error {
+	for _, seeder := range seeders {
+		if seeder == nil || seeder.Name() == "" {
+			return gmterrors.New(errors.New("seeder name is empty"))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, seeder := range seeders {
+		if i := slices.IndexFunc(r.seeders, func(s NamedSeeder) bool { return s.Name() == seeder.Name() }); i >= 0 {
+			r.seeders[i] = seeder
+		} else {
+			r.seeders = append(r.seeders, seeder)
+		}
+	}
+	return nil
+}
+
+// Seeders returns the registered seeders, in registration order.
+func (r *ModelRegistry) Seeders() []NamedSeeder {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.seeders)
+}
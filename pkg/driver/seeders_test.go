@@ -0,0 +1,30 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedSeeder struct {
+	name    string
+	version int
+}
+
+func (s namedSeeder) Name() string { return s.name }
+
+func TestModelRegistry_RegisterSeeders(t *testing.T) {
+	r := &ModelRegistry{}
+	require.NoError(t, r.RegisterSeeders(namedSeeder{name: "roles"}, namedSeeder{name: "settings"}))
+	require.NoError(t, r.RegisterSeeders(namedSeeder{name: "roles", version: 2}, namedSeeder{name: "lookups"}))
+	assert.Equal(t, []NamedSeeder{
+		namedSeeder{name: "roles", version: 2},
+		namedSeeder{name: "settings"},
+		namedSeeder{name: "lookups"},
+	}, r.Seeders())
+
+	err := r.RegisterSeeders(namedSeeder{name: "valid"}, namedSeeder{})
+	require.Error(t, err, "expected error for empty seeder name")
+	assert.Len(t, r.Seeders(), 3, "registry should be unchanged")
+}
@@ -0,0 +1,265 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TagName is the struct tag key used to declare the tenancy of a model, as an alternative to
+// implementing [TenantTabler]. Supported values are [TagShared] and [TagTenant].
+//
+// Example:
+//
+//	type User struct {
+//		_ struct{} `gmt:"shared"`
+//		gorm.Model
+//		Email string
+//	}
+const TagName = "gmt"
+
+const (
+	// TagShared declares a model as shared across tenants.
+	TagShared = "shared"
+	// TagTenant declares a model as tenant-specific.
+	TagTenant = "tenant"
+)
+
+type (
+	// SharedTable can be embedded in a model struct to declare it as shared across tenants, as
+	// an alternative to implementing [TenantTabler].
+	//
+	// Example:
+	//
+	// 	type User struct {
+	// 		driver.SharedTable
+	// 		gorm.Model
+	// 		Email string
+	// 	}
+	SharedTable struct{}
+
+	// TenantTable can be embedded in a model struct to declare it as tenant-specific, as an
+	// alternative to implementing [TenantTabler].
+	//
+	// Example:
+	//
+	// 	type Product struct {
+	// 		driver.TenantTable
+	// 		gorm.Model
+	// 		Name string
+	// 	}
+	TenantTable struct{}
+)
+
+var (
+	sharedTableType = reflect.TypeOf(SharedTable{})
+	tenantTableType = reflect.TypeOf(TenantTable{})
+)
+
+// DeclaredTenancy reports whether the model declares its tenancy with an embedded [SharedTable]
+// or [TenantTable], or a [TagName] struct tag, and if so, whether it is shared across tenants.
+// Embedded structs are searched as well. It returns an error if the model declares conflicting
+// tenancies or uses an unsupported tag value.
+func DeclaredTenancy(model interface{}) (shared, ok bool, err error) {
+	typ := reflect.TypeOf(model)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return false, false, nil
+	}
+	var found []bool
+	if err := collectTenancy(typ, &found); err != nil {
+		return false, false, fmt.Errorf("model %s: %w", typ, err)
+	}
+	if len(found) == 0 {
+		return false, false, nil
+	}
+	for _, s := range found[1:] {
+		if s != found[0] {
+			return false, false, fmt.Errorf("model %s declares both shared and tenant tenancy", typ)
+		}
+	}
+	return found[0], true, nil
+}
+
+// collectTenancy appends the tenancies declared by the fields of typ to found.
+func collectTenancy(typ reflect.Type, found *[]bool) error {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		switch {
+		case field.Type == sharedTableType:
+			*found = append(*found, true)
+			continue
+		case field.Type == tenantTableType:
+			*found = append(*found, false)
+			continue
+		}
+		if tag, ok := field.Tag.Lookup(TagName); ok {
+			switch tag {
+			case TagShared:
+				*found = append(*found, true)
+			case TagTenant:
+				*found = append(*found, false)
+			default:
+				return fmt.Errorf("unsupported %s tag value %q on field %s", TagName, tag, field.Name)
+			}
+		}
+		if field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := collectTenancy(embedded, found); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// declaredModel adapts a model that declares its tenancy to the [TenantTabler] interface.
+type declaredModel struct {
+	model     interface{}
+	tableName string
+	shared    bool
+}
+
+var _ TenantTabler = new(declaredModel)
+
+// TableName implements [schema.Tabler].
+func (m *declaredModel) TableName() string { return m.tableName }
+
+// IsSharedModel implements [TenantTabler].
+func (m *declaredModel) IsSharedModel() bool { return m.shared }
+
+// UnwrapModel returns the model as provided at registration. Models that declare their tenancy
+// are registered as [TenantTabler] adapters; the original model is what GORM operates on.
+func UnwrapModel(model TenantTabler) interface{} {
+	if declared, ok := model.(*declaredModel); ok {
+		return declared.model
+	}
+	return model
+}
+
+// ResolveModels converts the models to [TenantTabler] for registration with the db. Models that
+// implement [TenantTabler] are returned as is. The tenancy of other models is read from an embedded
+// [SharedTable] or [TenantTable], or a [TagName] struct tag, and their table names are computed
+// once, with the naming strategy of the db, qualifying the table names of shared models with the
+// public schema. Once registered, the statements of such shared models are directed to the
+// qualified table by the callbacks installed with [RegisterTenancyCallbacks].
+// Not intended for direct use in application code.
+func ResolveModels(db *gorm.DB, models ...interface{}) ([]TenantTabler, error) {
+	resolved := make([]TenantTabler, 0, len(models))
+	for _, model := range models {
+		if model == nil {
+			return nil, gmterrors.New(errors.New("cannot register a nil model"))
+		}
+		if tabler, ok := model.(TenantTabler); ok {
+			resolved = append(resolved, tabler)
+			continue
+		}
+		shared, ok, err := DeclaredTenancy(model)
+		if err != nil {
+			return nil, gmterrors.New(err)
+		}
+		if !ok {
+			return nil, gmterrors.New(fmt.Errorf(
+				"model %T must implement driver.TenantTabler or declare its tenancy with an embedded driver.SharedTable or driver.TenantTable, or a %s:%q or %s:%q struct tag",
+				model, TagName, TagShared, TagName, TagTenant,
+			))
+		}
+
+		var tableName string
+		switch tabler := model.(type) {
+		case schema.Tabler:
+			// GORM uses the table name as is, so it must be qualified by the model itself.
+			tableName = tabler.TableName()
+		case schema.TablerWithNamer:
+			tableName = tabler.TableName(namerOf(db))
+		default:
+			tableName = namerOf(db).TableName(modelType(model).Name())
+			if shared {
+				tableName = qualifyTableName(tableName, PublicSchemaOf(db))
+			}
+		}
+		resolved = append(resolved, &declaredModel{model: model, tableName: tableName, shared: shared})
+	}
+	return resolved, nil
+}
+
+// ResolveRegistrableModels is like [ResolveModels], but skips the models that cannot be registered,
+// rather than returning an error. It is used to unregister models, which ignores models that are
+// not registered. Not intended for direct use in application code.
+func ResolveRegistrableModels(db *gorm.DB, models ...interface{}) []TenantTabler {
+	resolved := make([]TenantTabler, 0, len(models))
+	for _, model := range models {
+		if r, err := ResolveModels(db, model); err == nil {
+			resolved = append(resolved, r...)
+		}
+	}
+	return resolved
+}
+
+// modelType returns the struct type of the model.
+func modelType(model interface{}) reflect.Type {
+	typ := reflect.TypeOf(model)
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// namerOf returns the naming strategy of the db, or GORM's default naming strategy if not set.
+func namerOf(db *gorm.DB) schema.Namer {
+	if db != nil && db.Config != nil && db.NamingStrategy != nil {
+		return db.NamingStrategy
+	}
+	return schema.NamingStrategy{}
+}
+
+// qualifyTableName prefixes the table name with the public schema, unless it is already qualified.
+func qualifyTableName(tableName, public string) string {
+	if strings.Contains(tableName, ".") {
+		return tableName
+	}
+	return public + "." + tableName
+}
+
+// qualifyTableCallbackName is the name of the callback registered by [RegisterTenancyCallbacks].
+const qualifyTableCallbackName = "gmt:qualify_table"
+
+// RegisterTenancyCallbacks registers GORM callbacks that direct the statements of the registered
+// shared models that declare their tenancy to their qualified table names (see
+// [ModelRegistry.DeclaredSharedTable]). Statements for which a table was set explicitly, such as
+// with [gorm.DB.Table], are left as is. It must be called before the db is used concurrently,
+// such as from the Initialize method of the dialector. Not intended for direct use in application code.
+func RegisterTenancyCallbacks(db *gorm.DB, registry *ModelRegistry) error {
+	if registry == nil {
+		return nil
+	}
+	qualify := func(db *gorm.DB) {
+		stmt := db.Statement
+		if stmt.Schema == nil || stmt.Table != stmt.Schema.Table {
+			return
+		}
+		if tableName, ok := registry.DeclaredSharedTable(stmt.Schema.ModelType); ok {
+			stmt.Table = tableName
+		}
+	}
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(qualifyTableCallbackName, qualify),
+		callbacks.Query().Before("gorm:query").Register(qualifyTableCallbackName, qualify),
+		callbacks.Update().Before("gorm:update").Register(qualifyTableCallbackName, qualify),
+		callbacks.Delete().Before("gorm:delete").Register(qualifyTableCallbackName, qualify),
+		callbacks.Row().Before("gorm:row").Register(qualifyTableCallbackName, qualify),
+	)
+}
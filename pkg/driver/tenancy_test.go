@@ -0,0 +1,164 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type declaredSharedModel struct {
+	SharedTable
+	ID   uint
+	Name string
+}
+
+type declaredTenantModel struct {
+	_    struct{} `gmt:"tenant"`
+	ID   uint
+	Name string
+}
+
+type embeddedTenancy struct {
+	TenantTable
+}
+
+type declaredEmbeddedModel struct {
+	embeddedTenancy
+	ID uint
+}
+
+type declaredTablerModel struct {
+	_  struct{} `gmt:"shared"`
+	ID uint
+}
+
+func (declaredTablerModel) TableName() string { return "tablers" }
+
+type conflictingModel struct {
+	SharedTable
+	_  struct{} `gmt:"tenant"`
+	ID uint
+}
+
+type unsupportedTagModel struct {
+	_  struct{} `gmt:"global"`
+	ID uint
+}
+
+type undeclaredModel struct {
+	ID uint
+}
+
+func TestDeclaredTenancy(t *testing.T) {
+	tests := []struct {
+		name       string
+		model      interface{}
+		wantShared bool
+		wantOK     bool
+		wantErr    bool
+	}{
+		{name: "embedded shared marker", model: &declaredSharedModel{}, wantShared: true, wantOK: true},
+		{name: "tenant tag", model: &declaredTenantModel{}, wantOK: true},
+		{name: "nested marker", model: declaredEmbeddedModel{}, wantOK: true},
+		{name: "slice of models", model: []declaredSharedModel{}, wantShared: true, wantOK: true},
+		{name: "undeclared", model: &undeclaredModel{}},
+		{name: "not a struct", model: "users"},
+		{name: "conflicting", model: &conflictingModel{}, wantErr: true},
+		{name: "unsupported tag value", model: &unsupportedTagModel{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shared, ok, err := DeclaredTenancy(tt.model)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantShared, shared)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestResolveModels(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	t.Run("declared models", func(t *testing.T) {
+		resolved, err := ResolveModels(db, &declaredSharedModel{}, &declaredTenantModel{}, tenantModel{})
+		require.NoError(t, err)
+		require.Len(t, resolved, 3)
+
+		assert.True(t, resolved[0].IsSharedModel())
+		assert.Equal(t, "public.declared_shared_models", resolved[0].TableName())
+		assert.False(t, resolved[1].IsSharedModel())
+		assert.Equal(t, "declared_tenant_models", resolved[1].TableName())
+		assert.Equal(t, tenantModel{}, resolved[2], "expected TenantTabler models to be returned as is")
+		assert.Equal(t, []interface{}{&declaredSharedModel{}, &declaredTenantModel{}, tenantModel{}}, ModelsToInterfaces(resolved))
+
+		registry, err := NewModelRegistry(resolved...)
+		require.NoError(t, err)
+		assert.Equal(t, []RegisteredModel{{Model: &declaredSharedModel{}, TableName: "public.declared_shared_models"}}, registry.Snapshot().Shared)
+
+		tableName, ok := registry.DeclaredSharedTable(reflect.TypeOf(declaredSharedModel{}))
+		assert.True(t, ok)
+		assert.Equal(t, "public.declared_shared_models", tableName)
+		_, ok = registry.DeclaredSharedTable(reflect.TypeOf(declaredTenantModel{}))
+		assert.False(t, ok, "expected tenant models not to be qualified")
+
+		registry.Unregister(resolved[0])
+		_, ok = registry.DeclaredSharedTable(reflect.TypeOf(declaredSharedModel{}))
+		assert.False(t, ok, "expected unregistered models to be dropped from the index")
+	})
+
+	t.Run("unqualified table name", func(t *testing.T) {
+		resolved, err := ResolveModels(db, &declaredTablerModel{})
+		require.NoError(t, err)
+		_, err = NewModelRegistry(resolved...)
+		assert.Error(t, err, "expected shared models with an unqualified TableName to fail validation")
+	})
+
+	t.Run("undeclared model", func(t *testing.T) {
+		_, err := ResolveModels(db, &undeclaredModel{})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid declaration", func(t *testing.T) {
+		_, err := ResolveModels(db, &conflictingModel{})
+		assert.Error(t, err)
+	})
+}
+
+func TestRegisterTenancyCallbacks(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	// Parsed before registration, so that GORM caches the unqualified table name.
+	stmt := &gorm.Statement{DB: db}
+	require.NoError(t, stmt.Parse(&declaredSharedModel{}))
+
+	registry := &ModelRegistry{}
+	require.NoError(t, RegisterTenancyCallbacks(db, registry))
+	resolved, err := ResolveModels(db, &declaredSharedModel{}, &declaredTenantModel{})
+	require.NoError(t, err)
+	require.NoError(t, registry.Register(resolved...))
+
+	sql := func(tx *gorm.DB) string { return tx.Statement.SQL.String() }
+
+	assert.Contains(t, sql(db.Find(&[]declaredSharedModel{})), "`public`.`declared_shared_models`")
+	assert.Contains(t, sql(db.Create(&declaredSharedModel{Name: "a"})), "`public`.`declared_shared_models`")
+	assert.Contains(t, sql(db.Model(&declaredSharedModel{ID: 1}).Update("name", "b")), "`public`.`declared_shared_models`")
+	assert.Contains(t, sql(db.Delete(&declaredSharedModel{ID: 1})), "`public`.`declared_shared_models`")
+	assert.Contains(t, sql(db.Find(&[]declaredTenantModel{})), "FROM `declared_tenant_models`")
+	assert.Contains(t, sql(db.Table("archive").Find(&[]declaredSharedModel{})), "FROM `archive`", "expected explicit tables to be left as is")
+
+	// A model of the same struct name in another package.
+	type declaredSharedModel struct {
+		ID uint
+	}
+	assert.Contains(t, sql(db.Find(&[]declaredSharedModel{})), "FROM `declared_shared_models`", "expected unregistered models to be left as is")
+}
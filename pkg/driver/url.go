@@ -89,6 +89,7 @@ func ParseDSNQueryParams[T any](dsn string) (params T, err error) {
 		WeaklyTypedInput: true,
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
 			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
 		),
 	}
 
@@ -1,10 +1,11 @@
 package driver
 
-// ModelsToInterfaces converts a slice of [TenantTabler] models to a slice of interface{}.
+// ModelsToInterfaces converts a slice of [TenantTabler] models to a slice of interface{},
+// unwrapping models that declare their tenancy (see [UnwrapModel]).
 func ModelsToInterfaces(models []TenantTabler) []interface{} {
 	interfaceModels := make([]interface{}, len(models))
 	for i, model := range models {
-		interfaceModels[i] = model
+		interfaceModels[i] = UnwrapModel(model)
 	}
 	return interfaceModels
 }
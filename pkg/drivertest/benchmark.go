@@ -95,13 +95,18 @@ func benchmarkOffboardTenant(b *testing.B, db *multitenancy.DB, _ Options) {
 	tenant := &testmodels.Tenant{ID: "tenant1"}
 	setupModels(b, db, tenant)
 
+	// Each iteration offboards a tenant of its own, as a tenant cannot be offboarded while it is
+	// being migrated, nor migrated once it has been archived.
+	var nextID atomic.Uint32
+
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			err := db.MigrateTenantModels(context.Background(), tenant.ID)
+			tenantID := fmt.Sprintf("tenant_offboard%d", nextID.Add(1))
+			err := db.MigrateTenantModels(context.Background(), tenantID)
 			require.NoError(b, err)
 
-			err = db.OffboardTenant(context.Background(), tenant.ID)
+			err = db.OffboardTenant(context.Background(), tenantID)
 			require.NoError(b, err)
 		}
 	})
@@ -3,12 +3,15 @@ package drivertest
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
 
 	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
 	"github.com/bartventer/gorm-multitenancy/v8/internal/testmodels"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -52,7 +55,11 @@ func RunConformanceTests(t *testing.T, newHarness HarnessMaker[*testing.T]) {
 	t.Run("RegisterModels", func(t *testing.T) { parallel(t, newHarness, testRegisterModels) })
 	t.Run("MigrateSharedModels", func(t *testing.T) { parallel(t, newHarness, testMigrateSharedModels) })
 	t.Run("MigrateTenantModels", func(t *testing.T) { parallel(t, newHarness, testMigrateTenantModels) })
+	t.Run("MigrateTenants", func(t *testing.T) { parallel(t, newHarness, testMigrateTenants) })
+	t.Run("ModelGroups", func(t *testing.T) { parallel(t, newHarness, testModelGroups) })
+	t.Run("ProvisionTenant", func(t *testing.T) { parallel(t, newHarness, testProvisionTenant) })
 	t.Run("OffboardTenant", func(t *testing.T) { parallel(t, newHarness, testOffboardTenant) })
+	t.Run("TenantLifecycle", func(t *testing.T) { parallel(t, newHarness, testTenantLifecycle) })
 	t.Run("UseTenant", func(t *testing.T) { parallel(t, newHarness, testUseTenant) })
 	t.Run("WithTenant", func(t *testing.T) { parallel(t, newHarness, testWithTenant) })
 	t.Run("CurrentTenant", func(t *testing.T) { parallel(t, newHarness, testCurrentTenant) })
@@ -66,7 +73,7 @@ func RunConformanceTests(t *testing.T, newHarness HarnessMaker[*testing.T]) {
 }
 
 // testRegisterModels tests the RegisterModels method.
-func testRegisterModels(t *testing.T, db *multitenancy.DB, _ Options) {
+func testRegisterModels(t *testing.T, db *multitenancy.DB, opts Options) {
 	t.Run("valid models", func(t *testing.T) {
 		err := db.RegisterModels(context.Background(), testmodels.MakeAllModels(t)...)
 		assert.NoError(t, err)
@@ -81,6 +88,63 @@ func testRegisterModels(t *testing.T, db *multitenancy.DB, _ Options) {
 		err := db.RegisterModels(context.Background(), &testmodels.BookInvalid{})
 		assert.Error(t, err, "expected error, got nil")
 	})
+
+	t.Run("shared model referencing tenant model", func(t *testing.T) {
+		err := db.RegisterModels(context.Background(), &testmodels.Book{}, &testmodels.PublisherInvalid{})
+		require.Error(t, err, "expected error, got nil")
+		assert.ErrorContains(t, err, "FeaturedBook")
+	})
+
+	tableNames := func(models []driver.RegisteredModel) []string {
+		names := make([]string, 0, len(models))
+		for _, model := range models {
+			names = append(names, model.TableName)
+		}
+		return names
+	}
+
+	t.Run("additive registration", func(t *testing.T) {
+		if opts.IsMock {
+			t.Skip("skipping registered models test for mock implementations")
+		}
+		ctx := context.Background()
+		require.NoError(t, db.RegisterModels(ctx, testmodels.MakeAllModels(t)...))
+		require.NoError(t, db.RegisterModels(ctx, &testmodels.Review{}))
+
+		snapshot := db.RegisteredModels()
+		assert.Contains(t, tableNames(snapshot.Shared), (&testmodels.Tenant{}).TableName())
+		assert.Contains(t, tableNames(snapshot.Tenant), (&testmodels.Book{}).TableName())
+		assert.Contains(t, tableNames(snapshot.Tenant), (&testmodels.Review{}).TableName())
+
+		require.NoError(t, db.UnregisterModels(ctx, &testmodels.Review{}))
+		assert.NotContains(t, tableNames(db.RegisteredModels().Tenant), (&testmodels.Review{}).TableName())
+		assert.Contains(t, tableNames(snapshot.Tenant), (&testmodels.Review{}).TableName(), "expected snapshot to be unaffected")
+	})
+
+	t.Run("declared models", func(t *testing.T) {
+		if opts.IsMock {
+			t.Skip("skipping declared models test for mock implementations")
+		}
+		ctx := context.Background()
+		require.NoError(t, db.RegisterModels(ctx, &testmodels.Plan{}, &testmodels.Note{}))
+		t.Cleanup(func() {
+			_ = db.UnregisterModels(ctx, &testmodels.Plan{}, &testmodels.Note{})
+		})
+		public := db.PublicSchema()
+		snapshot := db.RegisteredModels()
+		assert.Contains(t, tableNames(snapshot.Shared), public+".plans")
+		assert.Contains(t, tableNames(snapshot.Tenant), "notes")
+
+		require.NoError(t, db.MigrateSharedModels(ctx))
+		plan := &testmodels.Plan{Name: "basic"}
+		require.NoError(t, db.Create(plan).Error)
+
+		var got testmodels.Plan
+		require.NoError(t, db.Table(public+".plans").First(&got, plan.ID).Error, "expected the shared model to be stored in the public schema")
+		assert.Equal(t, "basic", got.Name)
+		require.NoError(t, db.First(&got, plan.ID).Error)
+		require.NoError(t, db.Delete(&got).Error)
+	})
 }
 
 // testMigrateSharedModels tests the MigrateSharedModels method.
@@ -137,6 +201,59 @@ func testMigrateTenantModels(t *testing.T, db *multitenancy.DB, opts Options) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("model fingerprint", func(t *testing.T) {
+		if opts.IsMock {
+			t.Skip("skipping fingerprint test for mock implementations")
+		}
+		ctx := context.Background()
+		models := testmodels.MakePrivateModels(t)
+		err := db.RegisterModels(ctx, models...)
+		require.NoError(t, err)
+
+		err = db.MigrateTenantModels(ctx, tenant.ID)
+		require.NoError(t, err)
+
+		want, err := migrator.Fingerprint(db.DB, models...)
+		require.NoError(t, err)
+		got, err := migrator.LoadFingerprint(db.DB, tenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "stored fingerprint does not match the registered models")
+
+		err = db.MigrateTenantModels(ctx, tenant.ID, migrator.WithForce())
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrent migrations", func(t *testing.T) {
+		if opts.IsMock {
+			t.Skip("skipping concurrency test for mock implementations")
+		}
+		ctx := context.Background()
+		models := testmodels.MakePrivateModels(t)
+		err := db.RegisterModels(ctx, models...)
+		require.NoError(t, err)
+
+		const tenantID = "tenant_concurrent"
+		var wg sync.WaitGroup
+		errs := make([]error, 4)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = db.MigrateTenantModels(ctx, tenantID)
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+
+		want, err := migrator.Fingerprint(db.DB, models...)
+		require.NoError(t, err)
+		got, err := migrator.LoadFingerprint(db.DB, tenantID)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "stored fingerprint does not match the registered models")
+	})
+
 	t.Run("Issue100 Quoting", func(t *testing.T) {
 		if opts.IsMock {
 			t.Skip("skipping case sensitive test for mock implementations")
@@ -152,6 +269,427 @@ func testMigrateTenantModels(t *testing.T, db *multitenancy.DB, opts Options) {
 		err = db.MigrateTenantModels(ctx, tenant.ID)
 		assert.NoError(t, err)
 	})
+
+	t.Run("SQL objects", func(t *testing.T) {
+		if opts.IsMock {
+			t.Skip("skipping SQL object test for mock implementations")
+		}
+		ctx := context.Background()
+		err := db.RegisterModels(ctx, testmodels.MakePrivateModels(t)...)
+		require.NoError(t, err)
+
+		view := driver.SQLObject{
+			Kind:      driver.SQLObjectView,
+			Name:      "book_titles",
+			CreateSQL: "CREATE VIEW book_titles AS SELECT id, title FROM books",
+			DropSQL:   "DROP VIEW IF EXISTS book_titles",
+		}
+		err = db.RegisterObjects(ctx, view)
+		require.NoError(t, err)
+		err = db.MigrateTenantModels(ctx, tenant.ID)
+		require.NoError(t, err)
+
+		countTitles := func(t *testing.T, column string) int64 {
+			t.Helper()
+			var count int64
+			err := db.WithTenant(ctx, tenant.ID, func(tx *multitenancy.DB) error {
+				return tx.Raw("SELECT COUNT(" + column + ") FROM book_titles").Scan(&count).Error
+			})
+			require.NoError(t, err)
+			return count
+		}
+		assert.Zero(t, countTitles(t, "id"))
+
+		// Changing the definition recreates the view on the next migration.
+		view.CreateSQL = "CREATE VIEW book_titles AS SELECT id AS book_id, title FROM books"
+		err = db.RegisterObjects(ctx, view)
+		require.NoError(t, err)
+		err = db.MigrateTenantModels(ctx, tenant.ID)
+		require.NoError(t, err)
+		assert.Zero(t, countTitles(t, "book_id"))
+
+		err = db.RegisterObjects(ctx, driver.SQLObject{Name: "orphan", CreateSQL: "SELECT 1", DependsOn: []string{"missing"}})
+		assert.Error(t, err, "expected error for unknown dependency")
+	})
+
+	t.Run("seeders", func(t *testing.T) {
+		if opts.IsMock {
+			t.Skip("skipping seeder test for mock implementations")
+		}
+		ctx := context.Background()
+		err := db.RegisterModels(ctx, testmodels.MakePrivateModels(t)...)
+		require.NoError(t, err)
+
+		runs := make(map[string]int)
+		newSeeder := func(name string) multitenancy.Seeder {
+			return multitenancy.NewSeeder(name, func(ctx context.Context, tx *multitenancy.DB) error {
+				runs[name]++
+				return tx.Create(&testmodels.Language{Name: name}).Error
+			})
+		}
+		countLanguages := func(t *testing.T) int64 {
+			t.Helper()
+			var count int64
+			err := db.WithTenant(ctx, tenant.ID, func(tx *multitenancy.DB) error {
+				return tx.Model(&testmodels.Language{}).Count(&count).Error
+			})
+			require.NoError(t, err)
+			return count
+		}
+		before := countLanguages(t)
+
+		err = db.RegisterSeeders(ctx, newSeeder("first"))
+		require.NoError(t, err)
+		for range 2 {
+			err = db.MigrateTenantModels(ctx, tenant.ID)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, runs["first"], "seeder should run once")
+		assert.Equal(t, before+1, countLanguages(t))
+
+		err = db.RegisterSeeders(ctx, newSeeder("second"))
+		require.NoError(t, err)
+		err = db.MigrateTenantModels(ctx, tenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"first": 1, "second": 1}, runs, "only the new seeder should run")
+		assert.Equal(t, before+2, countLanguages(t))
+
+		var mu sync.Mutex
+		concurrentRuns := 0
+		err = db.RegisterSeeders(ctx, multitenancy.NewSeeder("concurrent", func(ctx context.Context, tx *multitenancy.DB) error {
+			mu.Lock()
+			concurrentRuns++
+			mu.Unlock()
+			return tx.Create(&testmodels.Language{Name: "concurrent"}).Error
+		}))
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		errs := make([]error, 4)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = db.MigrateTenantModels(ctx, tenant.ID)
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, concurrentRuns, "seeder should run once for concurrent migrations")
+		assert.Equal(t, before+3, countLanguages(t))
+
+		failing := multitenancy.NewSeeder("failing", func(ctx context.Context, tx *multitenancy.DB) error {
+			if err := tx.Create(&testmodels.Language{Name: "rolled back"}).Error; err != nil {
+				return err
+			}
+			return errors.New("seeder failed")
+		})
+		err = db.RegisterSeeders(ctx, failing)
+		require.NoError(t, err)
+		err = db.MigrateTenantModels(ctx, tenant.ID)
+		require.Error(t, err)
+		assert.Equal(t, before+3, countLanguages(t), "failed seeder should be rolled back")
+	})
+}
+
+// testProvisionTenant tests the ProvisionTenant method.
+func testProvisionTenant(t *testing.T, db *multitenancy.DB, opts Options) {
+	if opts.IsMock {
+		t.Skip("skipping provisioning test for mock implementations")
+	}
+	ctx := context.Background()
+	setupModels(t, db, nil, func(o *setupModelsOptions) {
+		o.SkipCreateTenant = true
+		o.SkipTenantMigration = true
+	})
+
+	// hasBooks also reports false if the tenant schema does not exist.
+	hasBooks := func(t *testing.T, tenantID string) bool {
+		t.Helper()
+		return db.Table(tenantID + ".books").Migrator().HasTable(&testmodels.Book{})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		err := db.ProvisionTenant(ctx, &testmodels.Tenant{ID: "tenant1"})
+		require.NoError(t, err)
+		assert.True(t, hasBooks(t, "tenant1"))
+		var count int64
+		require.NoError(t, db.Model(&testmodels.Tenant{}).Where("id = ?", "tenant1").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("invalid schema name", func(t *testing.T) {
+		err := db.ProvisionTenant(ctx, &testmodels.Tenant{ID: "pg_tenant"})
+		assert.Error(t, err)
+	})
+
+	t.Run("compensation", func(t *testing.T) {
+		err := db.RegisterSeeders(ctx, multitenancy.NewSeeder("failing", func(context.Context, *multitenancy.DB) error {
+			return errors.New("seeder failed")
+		}))
+		require.NoError(t, err)
+
+		err = db.ProvisionTenant(ctx, &testmodels.Tenant{ID: "tenant2"})
+		require.Error(t, err)
+		var count int64
+		require.NoError(t, db.Unscoped().Model(&testmodels.Tenant{}).Where("id = ?", "tenant2").Count(&count).Error)
+		assert.Zero(t, count, "tenant row should be deleted")
+		assert.False(t, hasBooks(t, "tenant2"), "tenant schema should be dropped")
+		state, err := db.TenantState(ctx, "tenant2")
+		require.NoError(t, err)
+		assert.Empty(t, state, "tenant state should be removed")
+
+		// The tenant can be provisioned again once the cause of the failure has been fixed.
+		err = db.RegisterSeeders(ctx, multitenancy.NewSeeder("failing", func(context.Context, *multitenancy.DB) error { return nil }))
+		require.NoError(t, err)
+		err = db.ProvisionTenant(ctx, &testmodels.Tenant{ID: "tenant2"})
+		require.NoError(t, err)
+		assert.True(t, hasBooks(t, "tenant2"))
+	})
+
+	t.Run("existing schema", func(t *testing.T) {
+		// The schema of the tenant exists, but the tenant was not provisioned.
+		require.NoError(t, db.MigrateTenantModels(ctx, "tenant3"))
+		require.NoError(t, migrator.DeleteTenantState(db.DB, "tenant3"))
+
+		err := db.RegisterSeeders(ctx, multitenancy.NewSeeder("failing_existing", func(context.Context, *multitenancy.DB) error {
+			return errors.New("seeder failed")
+		}))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = db.RegisterSeeders(ctx, multitenancy.NewSeeder("failing_existing", func(context.Context, *multitenancy.DB) error { return nil }))
+		})
+
+		err = db.ProvisionTenant(ctx, &testmodels.Tenant{ID: "tenant3"})
+		require.Error(t, err)
+		var count int64
+		require.NoError(t, db.Unscoped().Model(&testmodels.Tenant{}).Where("id = ?", "tenant3").Count(&count).Error)
+		assert.Zero(t, count, "tenant row should be deleted")
+		assert.True(t, hasBooks(t, "tenant3"), "schema that existed before provisioning should be kept")
+	})
+
+	t.Run("concurrent duplicate", func(t *testing.T) {
+		// Another call is provisioning the tenant.
+		require.NoError(t, db.TransitionTenant(ctx, "tenant4", migrator.TenantProvisioning))
+		require.NoError(t, db.Create(&testmodels.Tenant{ID: "tenant4"}).Error)
+
+		err := db.ProvisionTenant(ctx, &testmodels.Tenant{ID: "tenant4"})
+		require.Error(t, err)
+		state, err := db.TenantState(ctx, "tenant4")
+		require.NoError(t, err)
+		assert.Equal(t, migrator.TenantProvisioning, state, "state of the concurrent call should be kept")
+		var count int64
+		require.NoError(t, db.Model(&testmodels.Tenant{}).Where("id = ?", "tenant4").Count(&count).Error)
+		assert.Equal(t, int64(1), count, "tenant row of the concurrent call should be kept")
+	})
+}
+
+// testTenantLifecycle tests the lifecycle state of tenants.
+func testTenantLifecycle(t *testing.T, db *multitenancy.DB, opts Options) {
+	if opts.IsMock {
+		t.Skip("skipping lifecycle test for mock implementations")
+	}
+	ctx := context.Background()
+	setupModels(t, db, nil, func(o *setupModelsOptions) {
+		o.SkipCreateTenant = true
+		o.SkipTenantMigration = true
+	})
+
+	requireState := func(t *testing.T, tenantID string, want migrator.TenantState) {
+		t.Helper()
+		got, err := db.TenantState(ctx, tenantID)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	err := db.ProvisionTenant(ctx, &testmodels.Tenant{ID: "tenant1"})
+	require.NoError(t, err)
+	requireState(t, "tenant1", migrator.TenantActive)
+
+	// Migrating returns the tenant to its previous state.
+	err = db.TransitionTenant(ctx, "tenant1", migrator.TenantSuspended)
+	require.NoError(t, err)
+	err = db.MigrateTenantModels(ctx, "tenant1", migrator.WithForce())
+	require.NoError(t, err)
+	requireState(t, "tenant1", migrator.TenantSuspended)
+
+	// Tenants that are up to date are skipped, leaving their state as is.
+	err = db.TransitionTenant(ctx, "tenant1", migrator.TenantActive)
+	require.NoError(t, err)
+	err = db.MigrateTenantModels(ctx, "tenant1")
+	require.NoError(t, err)
+	requireState(t, "tenant1", migrator.TenantActive)
+	err = db.TransitionTenant(ctx, "tenant1", migrator.TenantSuspended)
+	require.NoError(t, err)
+
+	tenantIDs, err := db.TenantsByState(ctx, migrator.TenantSuspended)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant1"}, tenantIDs)
+
+	err = db.TransitionTenant(ctx, "tenant1", migrator.TenantOffboarding)
+	require.NoError(t, err)
+	err = db.MigrateTenantModels(ctx, "tenant1")
+	require.ErrorIs(t, err, migrator.ErrInvalidTransition, "offboarding tenant should not be migrated")
+	requireState(t, "tenant1", migrator.TenantOffboarding)
+
+	err = db.OffboardTenant(ctx, "tenant1")
+	require.NoError(t, err)
+	requireState(t, "tenant1", migrator.TenantArchived)
+	err = db.TransitionTenant(ctx, "tenant1", migrator.TenantActive)
+	require.ErrorIs(t, err, migrator.ErrInvalidTransition, "archived tenant should not become active")
+	err = db.MigrateTenantModels(ctx, "tenant1")
+	require.ErrorIs(t, err, migrator.ErrInvalidTransition, "archived tenant should not be migrated")
+	requireState(t, "tenant1", migrator.TenantArchived)
+
+	// Tenants created before their lifecycle was tracked are tracked from their first migration.
+	tenant2 := &testmodels.Tenant{ID: "tenant2"}
+	require.NoError(t, db.Create(tenant2).Error)
+	requireState(t, tenant2.ID, "")
+	err = db.MigrateTenantModels(ctx, tenant2.ID)
+	require.NoError(t, err)
+	requireState(t, tenant2.ID, migrator.TenantActive)
+	err = db.OffboardTenant(ctx, tenant2.ID)
+	require.NoError(t, err)
+	requireState(t, tenant2.ID, migrator.TenantArchived)
+
+	tenantIDs, err = db.TenantsByState(ctx, migrator.TenantArchived)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant1", "tenant2"}, tenantIDs)
+
+	// Concurrent migrations wait for each other, returning the tenant to its state before either.
+	err = db.ProvisionTenant(ctx, &testmodels.Tenant{ID: "tenant3"})
+	require.NoError(t, err)
+	err = db.TransitionTenant(ctx, "tenant3", migrator.TenantSuspended)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = db.MigrateTenantModels(ctx, "tenant3", migrator.WithForce())
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	requireState(t, "tenant3", migrator.TenantSuspended)
+
+	// A tenant left migrating by an interrupted migration can be recovered.
+	err = db.TransitionTenant(ctx, "tenant3", migrator.TenantMigrating)
+	require.NoError(t, err)
+	err = db.TransitionTenant(ctx, "tenant3", migrator.TenantMigrating)
+	require.ErrorIs(t, err, migrator.ErrInvalidTransition, "tenant should be migrated by one migration at a time")
+	err = db.TransitionTenant(ctx, "tenant3", migrator.TenantSuspended)
+	require.NoError(t, err)
+	requireState(t, "tenant3", migrator.TenantSuspended)
+}
+
+// testMigrateTenants tests the MigrateTenants and ResumeMigrationRun methods.
+func testMigrateTenants(t *testing.T, db *multitenancy.DB, opts Options) {
+	if opts.IsMock {
+		t.Skip("skipping migration run test for mock implementations")
+	}
+	tenants := []*testmodels.Tenant{{ID: "tenant1"}, {ID: "tenant2"}}
+	for _, tenant := range tenants {
+		setupModels(t, db, tenant, func(o *setupModelsOptions) { o.SkipTenantMigration = true })
+	}
+
+	t.Run("completed run", func(t *testing.T) {
+		ctx := context.Background()
+		runID, err := db.MigrateTenants(ctx, []string{tenants[0].ID, tenants[1].ID})
+		require.NoError(t, err)
+		require.NotEmpty(t, runID)
+
+		run, runTenants, err := migrator.LoadRun(db.DB, runID)
+		require.NoError(t, err)
+		assert.Equal(t, migrator.RunStatusCompleted, run.Status)
+		require.Len(t, runTenants, 2)
+		for i, runTenant := range runTenants {
+			assert.Equal(t, tenants[i].ID, runTenant.TenantID, "expected tenants in original order")
+			assert.Equal(t, migrator.RunStatusCompleted, runTenant.Status)
+			assert.Equal(t, run.Fingerprint, runTenant.Fingerprint)
+		}
+
+		err = db.ResumeMigrationRun(ctx, runID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed run", func(t *testing.T) {
+		ctx := context.Background()
+		// Exceeds the maximum length of a tenant identifier.
+		invalidID := strings.Repeat("x", 70)
+		runID, err := db.MigrateTenants(ctx, []string{tenants[0].ID, invalidID})
+		require.Error(t, err)
+		require.NotEmpty(t, runID)
+
+		run, runTenants, err := migrator.LoadRun(db.DB, runID)
+		require.NoError(t, err)
+		assert.Equal(t, migrator.RunStatusFailed, run.Status)
+		require.Len(t, runTenants, 2)
+		assert.Equal(t, migrator.RunStatusCompleted, runTenants[0].Status)
+		assert.Equal(t, migrator.RunStatusFailed, runTenants[1].Status)
+		assert.NotEmpty(t, runTenants[1].Error)
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		err := db.ResumeMigrationRun(context.Background(), "unknown")
+		assert.ErrorIs(t, err, migrator.ErrRunNotFound)
+	})
+}
+
+// testModelGroups tests the migration of model groups.
+func testModelGroups(t *testing.T, db *multitenancy.DB, opts Options) {
+	if opts.IsMock {
+		t.Skip("skipping model group test for mock implementations")
+	}
+	ctx := context.Background()
+	tenant := &testmodels.Tenant{ID: "tenant1"}
+	err := db.RegisterModels(ctx, append(testmodels.MakeAllModels(t), &testmodels.Review{})...)
+	require.NoError(t, err)
+	setupModels(t, db, tenant, func(o *setupModelsOptions) { o.SkipRegisterModels = true })
+
+	hasReviews := func(t *testing.T) bool {
+		t.Helper()
+		var exists bool
+		err := db.WithTenant(ctx, tenant.ID, func(tx *multitenancy.DB) error {
+			exists = tx.Migrator().HasTable(&testmodels.Review{})
+			return nil
+		})
+		require.NoError(t, err)
+		return exists
+	}
+
+	t.Run("default group only", func(t *testing.T) {
+		groups, err := db.TenantModelGroups(ctx, tenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{driver.DefaultModelGroup}, groups)
+		assert.False(t, hasReviews(t), "expected premium tables not to be migrated")
+	})
+
+	t.Run("subscribe", func(t *testing.T) {
+		err := db.SubscribeModelGroups(ctx, tenant.ID, "premium")
+		require.NoError(t, err)
+		err = db.MigrateTenantModels(ctx, tenant.ID)
+		require.NoError(t, err)
+
+		groups, err := db.TenantModelGroups(ctx, tenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{driver.DefaultModelGroup, "premium"}, groups)
+		assert.True(t, hasReviews(t), "expected premium tables to be migrated")
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		err := db.SubscribeModelGroups(ctx, tenant.ID, "unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("unsubscribe default group", func(t *testing.T) {
+		err := db.UnsubscribeModelGroups(ctx, tenant.ID, driver.DefaultModelGroup)
+		assert.Error(t, err)
+	})
 }
 
 // testOffboardTenant tests the OffboardTenant method.
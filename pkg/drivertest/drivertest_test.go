@@ -49,7 +49,7 @@ func (m *mockApater) CurrentTenant(ctx context.Context, db *gorm.DB) string {
 func (m *mockApater) MigrateSharedModels(ctx context.Context, db *gorm.DB) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	if len(m.registry.SharedModels) == 0 {
+	if len(m.registry.RegisteredSharedModels()) == 0 {
 		return errors.New("no shared models registered")
 	}
 	return nil
@@ -59,7 +59,7 @@ func (m *mockApater) MigrateSharedModels(ctx context.Context, db *gorm.DB) error
 func (m *mockApater) MigrateTenantModels(ctx context.Context, db *gorm.DB, tenantID string) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	if len(m.registry.TenantModels) == 0 {
+	if len(m.registry.RegisteredTenantModels()) == 0 {
 		return errors.New("no tenant models registered")
 	}
 	return nil
@@ -74,13 +74,12 @@ func (m *mockApater) OffboardTenant(ctx context.Context, db *gorm.DB, tenantID s
 
 // RegisterModels implements [driver.DBFactory].
 func (m *mockApater) RegisterModels(ctx context.Context, db *gorm.DB, models ...driver.TenantTabler) error {
-	gmtConfig, err := driver.NewModelRegistry(models...)
-	if err != nil {
+	validate := func(models []driver.TenantTabler) error {
+		return driver.ValidateRelationships(db, models...)
+	}
+	if err := m.registry.RegisterValidated(validate, models...); err != nil {
 		return fmt.Errorf("failed to register models: %w", err)
 	}
-	m.mu.Lock()
-	m.registry = gmtConfig
-	m.mu.Unlock()
 	return nil
 }
 
@@ -141,10 +140,7 @@ func newHarness[TB testing.TB](ctx context.Context, t TB) (Harness, error) {
 	return &harness{
 		db: db,
 		adpater: &mockApater{
-			registry: &driver.ModelRegistry{
-				SharedModels: make([]driver.TenantTabler, 0),
-				TenantModels: make([]driver.TenantTabler, 0),
-			},
+			registry:        &driver.ModelRegistry{},
 			CurrentTenantID: "public",
 		},
 	}, nil
@@ -0,0 +1,90 @@
+// Package fakedb provides a database for testing transaction handling without a database server,
+// such as the transaction middleware of the middleware packages. It records the transactions
+// begun, committed and rolled back, and the tenants used.
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	multitenancy "github.com/bartventer/gorm-multitenancy/v8"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+)
+
+// ConnPool is a [gorm.ConnPool] recording the transactions begun, committed and rolled back.
+type ConnPool struct {
+	gorm.ConnPool
+
+	// Opts are the options of the transactions begun, in order.
+	Opts []*sql.TxOptions
+
+	// Committed and RolledBack are the numbers of transactions committed and rolled back.
+	Committed, RolledBack int
+
+	// CommitErr is returned when a transaction is committed, if not nil; the transaction is
+	// not counted as committed then.
+	CommitErr error
+}
+
+// BeginTx begins a transaction.
+func (p *ConnPool) BeginTx(_ context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	p.Opts = append(p.Opts, opts)
+	return &tx{pool: p}, nil
+}
+
+type tx struct {
+	gorm.ConnPool
+	pool *ConnPool
+}
+
+func (t *tx) Commit() error {
+	if t.pool.CommitErr != nil {
+		return t.pool.CommitErr
+	}
+	t.pool.Committed++
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	t.pool.RolledBack++
+	return nil
+}
+
+// Driver is a [driver.DBFactory] recording the tenants used.
+type Driver struct {
+	// Tenants are the tenants used, in order.
+	Tenants []string
+
+	// Resets is the number of times the tenant was reset.
+	Resets int
+}
+
+var _ driver.DBFactory = (*Driver)(nil)
+
+func (d *Driver) RegisterModels(context.Context, *gorm.DB, ...driver.TenantTabler) error {
+	return nil
+}
+func (d *Driver) MigrateSharedModels(context.Context, *gorm.DB) error         { return nil }
+func (d *Driver) MigrateTenantModels(context.Context, *gorm.DB, string) error { return nil }
+func (d *Driver) OffboardTenant(context.Context, *gorm.DB, string) error      { return nil }
+func (d *Driver) CurrentTenant(context.Context, *gorm.DB) string              { return "" }
+func (d *Driver) UseTenant(_ context.Context, _ *gorm.DB, tenantID string) (func() error, error) {
+	d.Tenants = append(d.Tenants, tenantID)
+	return func() error { d.Resets++; return nil }, nil
+}
+
+// New returns a database backed by a [Driver] and a [ConnPool], which are returned as well.
+func New(t testing.TB) (*multitenancy.DB, *Driver, *ConnPool) {
+	t.Helper()
+	pool := new(ConnPool)
+	gdb, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{ConnPool: pool, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := new(Driver)
+	return multitenancy.NewDB(d, gdb), d, pool
+}
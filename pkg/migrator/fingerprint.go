@@ -0,0 +1,119 @@
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Fingerprint returns a stable hash of the parsed GORM schemas of the given models.
+//
+// The hash covers everything [gorm.Migrator.AutoMigrate] acts upon: table names, column
+// definitions, indexes, check and unique constraints, foreign key constraints and many2many
+// join tables. It does not depend on the order in which the models are provided, so it only
+// changes when the models themselves change.
+func Fingerprint(db *gorm.DB, models ...interface{}) (string, error) {
+	tables := make([]string, 0, len(models))
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return "", fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		tables = append(tables, describeSchema(db.Dialector, stmt.Schema)...)
+	}
+	sort.Strings(tables)
+	tables = slices.Compact(tables)
+
+	h := sha256.New()
+	for _, table := range tables {
+		_, _ = io.WriteString(h, table)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// describeSchema returns a canonical description of the table described by s, followed by
+// the descriptions of any join tables it owns.
+func describeSchema(dialector gorm.Dialector, s *schema.Schema) []string {
+	var (
+		sb  strings.Builder
+		out []string
+	)
+	fmt.Fprintf(&sb, "table %s\n", s.Table)
+
+	fields := make([]string, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("field %s %s pk=%t ai=%t notnull=%t unique=%t default=%q comment=%q size=%d precision=%d scale=%d\n",
+			field.DBName,
+			dialector.DataTypeOf(field),
+			field.PrimaryKey,
+			field.AutoIncrement,
+			field.NotNull,
+			field.Unique,
+			field.DefaultValue,
+			field.Comment,
+			field.Size,
+			field.Precision,
+			field.Scale,
+		))
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		sb.WriteString(field)
+	}
+
+	indexes := s.ParseIndexes()
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+	for _, idx := range indexes {
+		fmt.Fprintf(&sb, "index %s class=%q type=%q where=%q option=%q", idx.Name, idx.Class, idx.Type, idx.Where, idx.Option)
+		for _, opt := range idx.Fields {
+			name := opt.Expression
+			if opt.Field != nil {
+				name = opt.DBName
+			}
+			fmt.Fprintf(&sb, " (%s sort=%q collate=%q length=%d)", name, opt.Sort, opt.Collate, opt.Length)
+		}
+		sb.WriteString("\n")
+	}
+
+	checks := s.ParseCheckConstraints()
+	for _, name := range sortedKeys(checks) {
+		fmt.Fprintf(&sb, "check %s %s\n", name, checks[name].Constraint)
+	}
+
+	uniques := s.ParseUniqueConstraints()
+	for _, name := range sortedKeys(uniques) {
+		fmt.Fprintf(&sb, "unique %s %s\n", name, uniques[name].Field.DBName)
+	}
+
+	for _, name := range sortedKeys(s.Relationships.Relations) {
+		rel := s.Relationships.Relations[name]
+		if constraint := rel.ParseConstraint(); constraint != nil && constraint.Schema == s {
+			sql, vars := constraint.Build()
+			fmt.Fprintf(&sb, "constraint %s %s %v\n", constraint.Name, sql, vars)
+		}
+		if rel.JoinTable != nil {
+			out = append(out, describeSchema(dialector, rel.JoinTable)...)
+		}
+	}
+
+	return append(out, sb.String())
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package migrator
+
+import (
+	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type (
+	fingerprintAuthor struct {
+		gorm.Model
+		Name  string `gorm:"size:100"`
+		Books []*fingerprintBook
+	}
+
+	fingerprintBook struct {
+		gorm.Model
+		Title               string                 `gorm:"index"`
+		FingerprintAuthorID uint                   `gorm:"not null"`
+		Languages           []*fingerprintLanguage `gorm:"many2many:fingerprint_book_languages;"`
+	}
+
+	fingerprintLanguage struct {
+		gorm.Model
+		Code string `gorm:"size:2;uniqueIndex"`
+	}
+
+	// fingerprintBookV2 maps to the same table as [fingerprintBook] with an additional column.
+	fingerprintBookV2 struct {
+		fingerprintBook
+		ISBN string
+	}
+)
+
+func (fingerprintBookV2) TableName() string { return "fingerprint_books" }
+
+func TestFingerprint(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{})
+	require.NoError(t, err)
+
+	fingerprint := func(t *testing.T, models ...interface{}) string {
+		t.Helper()
+		fp, err := Fingerprint(db, models...)
+		require.NoError(t, err)
+		return fp
+	}
+
+	t.Run("stable", func(t *testing.T) {
+		want := fingerprint(t, &fingerprintAuthor{}, &fingerprintBook{}, &fingerprintLanguage{})
+		got := fingerprint(t, &fingerprintAuthor{}, &fingerprintBook{}, &fingerprintLanguage{})
+		assert.Len(t, want, 64)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("order independent", func(t *testing.T) {
+		want := fingerprint(t, &fingerprintAuthor{}, &fingerprintBook{}, &fingerprintLanguage{})
+		got := fingerprint(t, &fingerprintLanguage{}, &fingerprintAuthor{}, &fingerprintBook{})
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("model changed", func(t *testing.T) {
+		before := fingerprint(t, &fingerprintAuthor{}, &fingerprintBook{}, &fingerprintLanguage{})
+		after := fingerprint(t, &fingerprintAuthor{}, &fingerprintBookV2{}, &fingerprintLanguage{})
+		assert.NotEqual(t, before, after)
+	})
+
+	t.Run("model added", func(t *testing.T) {
+		before := fingerprint(t, &fingerprintAuthor{}, &fingerprintBook{})
+		after := fingerprint(t, &fingerprintAuthor{}, &fingerprintBook{}, &fingerprintLanguage{})
+		assert.NotEqual(t, before, after)
+	})
+
+	t.Run("invalid model", func(t *testing.T) {
+		_, err := Fingerprint(db, "invalid")
+		assert.Error(t, err)
+	})
+}
+
+func TestCombineFingerprint(t *testing.T) {
+	const fingerprint = "abc"
+	assert.Equal(t, fingerprint, CombineFingerprint(fingerprint), "fingerprint without objects should be unchanged")
+
+	view := driver.SQLObject{Kind: driver.SQLObjectView, Name: "v", CreateSQL: "CREATE VIEW v AS SELECT 1"}
+	combined := CombineFingerprint(fingerprint, view)
+	assert.NotEqual(t, fingerprint, combined)
+	assert.Equal(t, combined, CombineFingerprint(fingerprint, view), "fingerprint should be stable")
+
+	view.CreateSQL = "CREATE VIEW v AS SELECT 2"
+	assert.NotEqual(t, combined, CombineFingerprint(fingerprint, view), "fingerprint should change with the object")
+}
@@ -0,0 +1,222 @@
+package migrator
+
+import (
+	"slices"
+	"time"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantModelGroup records a tenant's subscription to a model group, along with the model
+// fingerprint of the group as of the last successful migration. It is stored in the public
+// schema. Not intended for direct use in application code.
+type TenantModelGroup struct {
+	TenantID    string     `gorm:"column:tenant_id;primaryKey;size:63"`
+	GroupName   string     `gorm:"column:group_name;primaryKey;size:63"`
+	Fingerprint string     `gorm:"column:fingerprint;size:64"`
+	MigratedAt  *time.Time `gorm:"column:migrated_at"`
+}
+
+var _ driver.TenantTabler = new(TenantModelGroup)
+
+// TableName implements [driver.TenantTabler].
+func (TenantModelGroup) TableName() string {
+	return driver.PublicSchemaName() + ".gmt_tenant_model_groups"
+}
+
+// IsSharedModel implements [driver.TenantTabler].
+func (TenantModelGroup) IsSharedModel() bool { return true }
+
+// LoadModelGroups returns the model groups the tenant subscribes to, mapped to the fingerprint
+// stored for each group by the last successful migration. [driver.DefaultModelGroup] is always
+// included.
+func LoadModelGroups(db *gorm.DB, tenantID string) (map[string]string, error) {
+	groups := map[string]string{driver.DefaultModelGroup: ""}
+	if !publicTable(db, &TenantModelGroup{}).Migrator().HasTable(&TenantModelGroup{}) {
+		return groups, nil
+	}
+	var records []TenantModelGroup
+	if err := publicTable(db, &TenantModelGroup{}).Where("tenant_id = ?", tenantID).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		groups[record.GroupName] = record.Fingerprint
+	}
+	return groups, nil
+}
+
+// SubscribeModelGroups subscribes the tenant to the given model groups. Existing subscriptions
+// are left unchanged.
+func SubscribeModelGroups(db *gorm.DB, tenantID string, groups ...string) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	if err := ensureTables(db, &TenantModelGroup{}); err != nil {
+		return err
+	}
+	records := make([]TenantModelGroup, 0, len(groups))
+	for _, group := range groups {
+		records = append(records, TenantModelGroup{TenantID: tenantID, GroupName: group})
+	}
+	return publicTable(db, &TenantModelGroup{}).Clauses(clause.OnConflict{DoNothing: true}).Create(&records).Error
+}
+
+// UnsubscribeModelGroups removes the tenant's subscriptions to the given model groups.
+func UnsubscribeModelGroups(db *gorm.DB, tenantID string, groups ...string) error {
+	if len(groups) == 0 || !publicTable(db, &TenantModelGroup{}).Migrator().HasTable(&TenantModelGroup{}) {
+		return nil
+	}
+	return publicTable(db, &TenantModelGroup{}).Where("tenant_id = ? AND group_name IN ?", tenantID, groups).Delete(&TenantModelGroup{}).Error
+}
+
+// deleteModelGroups removes all model group subscriptions of the tenant.
+func deleteModelGroups(db *gorm.DB, tenantID string) error {
+	if !publicTable(db, &TenantModelGroup{}).Migrator().HasTable(&TenantModelGroup{}) {
+		return nil
+	}
+	return publicTable(db, &TenantModelGroup{}).Where("tenant_id = ?", tenantID).Delete(&TenantModelGroup{}).Error
+}
+
+// TenantPlan describes the tenant models to migrate for a tenant, based on the model groups it
+// subscribes to and the fingerprints stored by its last successful migration.
+type TenantPlan struct {
+	// UpToDate reports whether the tenant is up to date, in which case there is nothing to migrate.
+	UpToDate bool
+
+	// Fingerprint is the fingerprint of all tenant models the tenant subscribes to, combined
+	// with the checksums of the registered tenant SQL objects.
+	Fingerprint string
+
+	// Models contains the tenant models of the model groups that changed since the last
+	// successful migration, in registration order.
+	Models []driver.TenantTabler
+
+	// Objects contains the registered tenant SQL objects, in dependency order. They are
+	// recreated whenever the tenant is migrated.
+	Objects []driver.SQLObject
+
+	tenantID string
+	groups   map[string]string // group name -> fingerprint
+}
+
+// PlanTenantMigration determines the tenant models to migrate for the tenant. Only the model
+// groups the tenant subscribes to are considered, and of those, only the groups whose models
+// changed since the last successful migration are included, unless [WithForce] was provided.
+func PlanTenantMigration(db *gorm.DB, registry *driver.ModelRegistry, tenantID string) (*TenantPlan, error) {
+	stored, err := LoadModelGroups(db, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]string, 0, len(stored))
+	for _, group := range registry.TenantModelGroups() {
+		if _, ok := stored[group]; ok {
+			groups = append(groups, group)
+		}
+	}
+
+	plan := &TenantPlan{
+		Objects:  registry.TenantObjects(),
+		tenantID: tenantID,
+		groups:   make(map[string]string, len(groups)),
+	}
+	plan.Fingerprint, err = Fingerprint(db, driver.ModelsToInterfaces(registry.TenantModelsByGroup(groups...))...)
+	if err != nil {
+		return nil, err
+	}
+	plan.Fingerprint = CombineFingerprint(plan.Fingerprint, plan.Objects...)
+
+	force := MigrateOptionsFromDB(db).Force
+	if !force {
+		current, loadErr := LoadFingerprint(db, tenantID)
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		if current == plan.Fingerprint {
+			plan.UpToDate = true
+			return plan, nil
+		}
+	}
+
+	changed := make([]string, 0, len(groups))
+	for _, group := range groups {
+		fingerprint, fpErr := Fingerprint(db, driver.ModelsToInterfaces(registry.TenantModelsByGroup(group))...)
+		if fpErr != nil {
+			return nil, fpErr
+		}
+		plan.groups[group] = fingerprint
+		if force || stored[group] != fingerprint {
+			changed = append(changed, group)
+		}
+	}
+	plan.Models = registry.TenantModelsByGroup(changed...)
+	return plan, nil
+}
+
+const tenantPlanKey key = pkgName + "/tenant_plan"
+
+// WithTenantPlan stores the plan on the database, from where drivers retrieve it using
+// [TenantPlanFromDB], so that a tenant is planned once per migration.
+func WithTenantPlan(plan *TenantPlan) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Set(string(tenantPlanKey), plan)
+	}
+}
+
+// TenantPlanFromDB returns the plan stored on the database for the tenant with [WithTenantPlan],
+// or plans the migration of the tenant if there is none (see [PlanTenantMigration]).
+func TenantPlanFromDB(db *gorm.DB, registry *driver.ModelRegistry, tenantID string) (*TenantPlan, error) {
+	if v, ok := db.Get(string(tenantPlanKey)); ok {
+		if plan, ok := v.(*TenantPlan); ok && plan != nil && plan.tenantID == tenantID {
+			return plan, nil
+		}
+	}
+	return PlanTenantMigration(db, registry, tenantID)
+}
+
+// Applied reports whether the fingerprint stored for the tenant matches the plan, such as once a
+// concurrent migration of the tenant has completed. It always reports false if [WithForce] was
+// provided. Drivers call it once they hold the migration lock of the tenant.
+func (p *TenantPlan) Applied(db *gorm.DB) (bool, error) {
+	if MigrateOptionsFromDB(db).Force {
+		return false, nil
+	}
+	current, err := LoadFingerprint(db, p.tenantID)
+	if err != nil {
+		return false, err
+	}
+	return current == p.Fingerprint, nil
+}
+
+// Save records the plan as successfully migrated for the tenant.
+func (p *TenantPlan) Save(db *gorm.DB) error {
+	if err := SaveFingerprint(db, p.tenantID, p.Fingerprint); err != nil {
+		return err
+	}
+	if err := ensureTables(db, &TenantModelGroup{}); err != nil {
+		return err
+	}
+	now := time.Now()
+	names := make([]string, 0, len(p.groups))
+	for group := range p.groups {
+		names = append(names, group)
+	}
+	slices.Sort(names)
+	records := make([]TenantModelGroup, 0, len(names))
+	for _, group := range names {
+		records = append(records, TenantModelGroup{
+			TenantID:    p.tenantID,
+			GroupName:   group,
+			Fingerprint: p.groups[group],
+			MigratedAt:  &now,
+		})
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return publicTable(db, &TenantModelGroup{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "group_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"fingerprint", "migrated_at"}),
+	}).Create(&records).Error
+}
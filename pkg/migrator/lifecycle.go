@@ -0,0 +1,163 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantState is the lifecycle state of a tenant.
+type TenantState string
+
+// Define values for [TenantState].
+const (
+	TenantProvisioning TenantState = "provisioning" // The tenant is being onboarded.
+	TenantActive       TenantState = "active"       // The tenant is in use.
+	TenantMigrating    TenantState = "migrating"    // The tables of the tenant are being migrated.
+	TenantSuspended    TenantState = "suspended"    // The tenant is temporarily disabled.
+	TenantOffboarding  TenantState = "offboarding"  // The tenant is being removed.
+	TenantArchived     TenantState = "archived"     // The tenant has been removed.
+)
+
+// ErrInvalidTransition is returned when a tenant cannot transition from its current state to the
+// requested state.
+var ErrInvalidTransition = errors.New("invalid tenant state transition")
+
+// tenantTransitions maps each state to the states a tenant may transition to. The empty state
+// is the state of tenants whose lifecycle is not tracked yet, such as tenants created before.
+// A tenant left migrating by an interrupted migration is recovered by transitioning it to the
+// state it was in before, or by offboarding it.
+var tenantTransitions = map[TenantState][]TenantState{
+	"":                 {TenantProvisioning, TenantActive, TenantMigrating, TenantSuspended, TenantOffboarding},
+	TenantProvisioning: {TenantActive, TenantMigrating, TenantOffboarding},
+	TenantActive:       {TenantMigrating, TenantSuspended, TenantOffboarding},
+	TenantMigrating:    {TenantProvisioning, TenantActive, TenantSuspended, TenantOffboarding},
+	TenantSuspended:    {TenantActive, TenantMigrating, TenantOffboarding},
+	TenantOffboarding:  {TenantArchived},
+	TenantArchived:     {TenantProvisioning},
+}
+
+// Valid reports whether s is a known tenant state.
+func (s TenantState) Valid() bool {
+	_, ok := tenantTransitions[s]
+	return ok && s != ""
+}
+
+// CanTransition reports whether a tenant in state s may transition to the given state.
+// Transitioning to the current state is allowed, except for [TenantMigrating], as a tenant is
+// migrated by one migration at a time.
+func (s TenantState) CanTransition(to TenantState) bool {
+	if !to.Valid() {
+		return false
+	}
+	if s == to {
+		return s != TenantMigrating
+	}
+	return slices.Contains(tenantTransitions[s], to)
+}
+
+// TenantLifecycle records the lifecycle state of a tenant. It is stored in the public schema of
+// the dialector. Not intended for direct use in application code.
+type TenantLifecycle struct {
+	TenantID  string      `gorm:"column:tenant_id;primaryKey;size:63"`
+	State     TenantState `gorm:"column:state;size:16;not null;index"`
+	UpdatedAt time.Time   `gorm:"column:updated_at;not null"`
+}
+
+var _ driver.TenantTabler = new(TenantLifecycle)
+
+// TableName implements [driver.TenantTabler].
+func (TenantLifecycle) TableName() string {
+	return driver.PublicSchemaName() + ".gmt_tenant_states"
+}
+
+// IsSharedModel implements [driver.TenantTabler].
+func (TenantLifecycle) IsSharedModel() bool { return true }
+
+// LoadTenantState returns the lifecycle state of the tenant, or an empty state if the lifecycle
+// of the tenant is not tracked.
+func LoadTenantState(db *gorm.DB, tenantID string) (TenantState, error) {
+	if !publicTable(db, &TenantLifecycle{}).Migrator().HasTable(&TenantLifecycle{}) {
+		return "", nil
+	}
+	var records []TenantLifecycle
+	if err := publicTable(db, &TenantLifecycle{}).Where("tenant_id = ?", tenantID).Limit(1).Find(&records).Error; err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "", nil
+	}
+	return records[0].State, nil
+}
+
+// TransitionTenantState transitions the tenant to the given state and returns its previous
+// state. The current state is read and updated within a transaction, locking the record of the
+// tenant. It returns an error wrapping [ErrInvalidTransition] if the transition is not allowed
+// (see [TenantState.CanTransition]).
+func TransitionTenantState(db *gorm.DB, tenantID string, to TenantState) (from TenantState, err error) {
+	if err := ensureTables(db, &TenantLifecycle{}); err != nil {
+		return "", err
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var records []TenantLifecycle
+		if err := publicTable(tx, &TenantLifecycle{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ?", tenantID).Limit(1).Find(&records).Error; err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			from = records[0].State
+		}
+		if !from.CanTransition(to) {
+			return fmt.Errorf("%w: tenant %s cannot transition from %q to %q", ErrInvalidTransition, tenantID, from, to)
+		}
+		record := &TenantLifecycle{TenantID: tenantID, State: to, UpdatedAt: time.Now()}
+		return publicTable(tx, record).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"state", "updated_at"}),
+		}).Create(record).Error
+	})
+	return from, err
+}
+
+// RevertTenantState reverts a transition of the tenant from the state from to the state to, made by
+// [TransitionTenantState], if the tenant is still in the state to. The lifecycle of the tenant is
+// no longer tracked if from is empty. The state of a tenant that has transitioned since is left
+// as is.
+func RevertTenantState(db *gorm.DB, tenantID string, to, from TenantState) error {
+	if !publicTable(db, &TenantLifecycle{}).Migrator().HasTable(&TenantLifecycle{}) {
+		return nil
+	}
+	tx := publicTable(db, &TenantLifecycle{}).Where("tenant_id = ? AND state = ?", tenantID, to)
+	if from == "" {
+		return tx.Delete(&TenantLifecycle{}).Error
+	}
+	return tx.Updates(map[string]interface{}{"state": from, "updated_at": time.Now()}).Error
+}
+
+// ListTenantsByState returns the identifiers of the tenants in any of the given states, ordered
+// by identifier.
+func ListTenantsByState(db *gorm.DB, states ...TenantState) ([]string, error) {
+	if len(states) == 0 || !publicTable(db, &TenantLifecycle{}).Migrator().HasTable(&TenantLifecycle{}) {
+		return nil, nil
+	}
+	var tenantIDs []string
+	err := publicTable(db, &TenantLifecycle{}).
+		Where("state IN ?", states).
+		Order("tenant_id").
+		Pluck("tenant_id", &tenantIDs).Error
+	return tenantIDs, err
+}
+
+// DeleteTenantState stops tracking the lifecycle of the tenant.
+func DeleteTenantState(db *gorm.DB, tenantID string) error {
+	if !publicTable(db, &TenantLifecycle{}).Migrator().HasTable(&TenantLifecycle{}) {
+		return nil
+	}
+	return publicTable(db, &TenantLifecycle{}).Where("tenant_id = ?", tenantID).Delete(&TenantLifecycle{}).Error
+}
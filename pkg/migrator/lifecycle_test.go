@@ -0,0 +1,43 @@
+package migrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantState_CanTransition(t *testing.T) {
+	tests := []struct {
+		from, to TenantState
+		want     bool
+	}{
+		{"", TenantProvisioning, true},
+		{"", TenantActive, true},
+		{"", TenantArchived, false},
+		{TenantProvisioning, TenantActive, true},
+		{TenantActive, TenantMigrating, true},
+		{TenantActive, TenantArchived, false},
+		{TenantActive, TenantActive, true},
+		{TenantMigrating, TenantMigrating, false},
+		{TenantMigrating, TenantOffboarding, true},
+		{TenantSuspended, TenantActive, true},
+		{TenantOffboarding, TenantMigrating, false},
+		{TenantOffboarding, TenantArchived, true},
+		{TenantArchived, TenantActive, false},
+		{TenantArchived, TenantProvisioning, true},
+		{TenantArchived, TenantMigrating, false},
+		{TenantActive, "", false},
+		{TenantActive, "unknown", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.from.CanTransition(tt.to))
+		})
+	}
+}
+
+func TestTenantState_Valid(t *testing.T) {
+	assert.True(t, TenantActive.Valid())
+	assert.False(t, TenantState("").Valid())
+	assert.False(t, TenantState("unknown").Valid())
+}
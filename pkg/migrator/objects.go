@@ -0,0 +1,50 @@
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"gorm.io/gorm"
+)
+
+// DropObjects drops the SQL objects, given in dependency order, in reverse order. Objects without
+// drop SQL are skipped.
+func DropObjects(db *gorm.DB, objects []driver.SQLObject) error {
+	for i := len(objects) - 1; i >= 0; i-- {
+		object := objects[i]
+		if object.DropSQL == "" {
+			continue
+		}
+		if err := db.Exec(object.DropSQL).Error; err != nil {
+			return fmt.Errorf("failed to drop %s %q: %w", object.Kind, object.Name, err)
+		}
+	}
+	return nil
+}
+
+// CreateObjects creates the SQL objects, given in dependency order.
+func CreateObjects(db *gorm.DB, objects []driver.SQLObject) error {
+	for _, object := range objects {
+		if err := db.Exec(object.CreateSQL).Error; err != nil {
+			return fmt.Errorf("failed to create %s %q: %w", object.Kind, object.Name, err)
+		}
+	}
+	return nil
+}
+
+// CombineFingerprint combines a model fingerprint, as returned by [Fingerprint], with the
+// checksums of the SQL objects. The fingerprint is returned as is if there are no objects.
+func CombineFingerprint(fingerprint string, objects ...driver.SQLObject) string {
+	if len(objects) == 0 {
+		return fingerprint
+	}
+	h := sha256.New()
+	_, _ = io.WriteString(h, fingerprint)
+	for _, object := range objects {
+		_, _ = io.WriteString(h, "\x00"+object.Checksum())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
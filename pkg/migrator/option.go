@@ -27,6 +27,48 @@ func WithOption(opt option) func(*gorm.DB) *gorm.DB {
 	}
 }
 
+const migrateOptionsKey key = pkgName + "/migrate_options"
+
+// MigrateOptions holds the options for migrating tenant models.
+type MigrateOptions struct {
+	// Force migrates the tenant even if its stored model fingerprint matches the
+	// fingerprint of the registered models.
+	Force bool
+}
+
+// MigrateOption is a function that modifies a [MigrateOptions] instance.
+type MigrateOption func(*MigrateOptions)
+
+// WithForce forces the migration of a tenant, regardless of its stored model fingerprint.
+func WithForce() MigrateOption {
+	return func(o *MigrateOptions) {
+		o.Force = true
+	}
+}
+
+// WithMigrateOptions stores the migrate options on the database, from where drivers retrieve
+// them using [MigrateOptionsFromDB].
+func WithMigrateOptions(opts ...MigrateOption) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		options := MigrateOptionsFromDB(db)
+		for _, opt := range opts {
+			opt(&options)
+		}
+		return db.Set(string(migrateOptionsKey), options)
+	}
+}
+
+// MigrateOptionsFromDB retrieves the migrate options from the database.
+// It returns the zero value if no options have been set.
+func MigrateOptionsFromDB(db *gorm.DB) MigrateOptions {
+	if v, ok := db.Get(string(migrateOptionsKey)); ok {
+		if options, ok := v.(MigrateOptions); ok {
+			return options
+		}
+	}
+	return MigrateOptions{}
+}
+
 // OptionFromDB retrieves the migration option from the database.
 // If the option is not found or is not of the correct type, it returns [driver.ErrInvalidMigration].
 func OptionFromDB(db *gorm.DB) (option, error) {
@@ -73,3 +73,30 @@ func TestOptionFromDB(t *testing.T) {
 		})
 	}
 }
+
+func TestMigrateOptionsFromDB(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{})
+	require.NoError(t, err)
+
+	assert.Equal(t, MigrateOptions{}, MigrateOptionsFromDB(db), "expected zero value when not set")
+
+	db = WithMigrateOptions(WithForce())(db)
+	assert.Equal(t, MigrateOptions{Force: true}, MigrateOptionsFromDB(db))
+
+	db = WithMigrateOptions()(db)
+	assert.Equal(t, MigrateOptions{Force: true}, MigrateOptionsFromDB(db), "expected existing options to be retained")
+}
+
+func TestTenantPlanFromDB(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{})
+	require.NoError(t, err)
+
+	plan := &TenantPlan{Fingerprint: "abc", tenantID: "tenant1"}
+	got, err := TenantPlanFromDB(WithTenantPlan(plan)(db), &driver.ModelRegistry{}, "tenant1")
+	require.NoError(t, err)
+	assert.Same(t, plan, got, "expected the stored plan to be reused")
+
+	applied, err := plan.Applied(WithMigrateOptions(WithForce())(db))
+	require.NoError(t, err)
+	assert.False(t, applied, "expected forced plans never to be applied")
+}
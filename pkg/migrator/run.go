@@ -0,0 +1,179 @@
+package migrator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"gorm.io/gorm"
+)
+
+// Define values for the status of a [MigrationRun] and [MigrationRunTenant].
+const (
+	RunStatusPending   = "pending"
+	RunStatusRunning   = "running"
+	RunStatusCompleted = "completed"
+	RunStatusFailed    = "failed"
+)
+
+// ErrRunNotFound is returned when a migration run does not exist.
+var ErrRunNotFound = errors.New("migration run not found")
+
+type (
+	// MigrationRun records a migration of the tenant models across multiple tenants.
+	// It is stored in the public schema. Not intended for direct use in application code.
+	MigrationRun struct {
+		ID          string    `gorm:"column:id;primaryKey;size:32"`
+		Fingerprint string    `gorm:"column:fingerprint;size:64;not null"`
+		Status      string    `gorm:"column:status;size:16;not null"`
+		CreatedAt   time.Time `gorm:"column:created_at;not null"`
+		UpdatedAt   time.Time `gorm:"column:updated_at;not null"`
+	}
+
+	// MigrationRunTenant records the status of a single tenant within a [MigrationRun].
+	// It is stored in the public schema. Not intended for direct use in application code.
+	MigrationRunTenant struct {
+		RunID       string    `gorm:"column:run_id;primaryKey;size:32"`
+		TenantID    string    `gorm:"column:tenant_id;primaryKey;size:63"`
+		Position    int       `gorm:"column:position;not null"`
+		Status      string    `gorm:"column:status;size:16;not null"`
+		Fingerprint string    `gorm:"column:fingerprint;size:64"`
+		Error       string    `gorm:"column:error"`
+		UpdatedAt   time.Time `gorm:"column:updated_at;not null"`
+	}
+)
+
+var _ driver.TenantTabler = new(MigrationRun)
+var _ driver.TenantTabler = new(MigrationRunTenant)
+
+// TableName implements [driver.TenantTabler].
+func (MigrationRun) TableName() string { return driver.PublicSchemaName() + ".gmt_migration_runs" }
+
+// IsSharedModel implements [driver.TenantTabler].
+func (MigrationRun) IsSharedModel() bool { return true }
+
+// TableName implements [driver.TenantTabler].
+func (MigrationRunTenant) TableName() string {
+	return driver.PublicSchemaName() + ".gmt_migration_run_tenants"
+}
+
+// IsSharedModel implements [driver.TenantTabler].
+func (MigrationRunTenant) IsSharedModel() bool { return true }
+
+// ensureTables creates the tables of the given models in the public schema if they do not exist yet.
+func ensureTables(db *gorm.DB, models ...driver.TenantTabler) error {
+	for _, model := range models {
+		tx := publicTable(db, model)
+		if tx.Migrator().HasTable(model) {
+			continue
+		}
+		if err := tx.Scopes(WithOption(MigratorOption)).AutoMigrate(model); err != nil {
+			if publicTable(db, model).Migrator().HasTable(model) {
+				continue // created concurrently
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// newRunID returns a new random run identifier.
+func newRunID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateRun persists a new migration run for the given tenants, in the given order, and returns it.
+func CreateRun(db *gorm.DB, fingerprint string, tenantIDs []string) (*MigrationRun, error) {
+	if err := ensureTables(db, &MigrationRun{}, &MigrationRunTenant{}); err != nil {
+		return nil, err
+	}
+	id, err := newRunID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	run := &MigrationRun{
+		ID:          id,
+		Fingerprint: fingerprint,
+		Status:      RunStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := publicTable(tx, run).Create(run).Error; err != nil {
+			return err
+		}
+		if len(tenantIDs) == 0 {
+			return nil
+		}
+		tenants := make([]MigrationRunTenant, 0, len(tenantIDs))
+		seen := make(map[string]struct{}, len(tenantIDs))
+		for _, tenantID := range tenantIDs {
+			if _, ok := seen[tenantID]; ok {
+				continue
+			}
+			seen[tenantID] = struct{}{}
+			tenants = append(tenants, MigrationRunTenant{
+				RunID:     id,
+				TenantID:  tenantID,
+				Position:  len(tenants),
+				Status:    RunStatusPending,
+				UpdatedAt: now,
+			})
+		}
+		return publicTable(tx, &MigrationRunTenant{}).Create(&tenants).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// LoadRun returns the migration run with the given identifier, along with its tenants in
+// migration order. It returns [ErrRunNotFound] if the run does not exist.
+func LoadRun(db *gorm.DB, runID string) (*MigrationRun, []MigrationRunTenant, error) {
+	if !publicTable(db, &MigrationRun{}).Migrator().HasTable(&MigrationRun{}) {
+		return nil, nil, ErrRunNotFound
+	}
+	var runs []MigrationRun
+	if err := publicTable(db, &MigrationRun{}).Where("id = ?", runID).Limit(1).Find(&runs).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(runs) == 0 {
+		return nil, nil, ErrRunNotFound
+	}
+	var tenants []MigrationRunTenant
+	if err := publicTable(db, &MigrationRunTenant{}).Where("run_id = ?", runID).Order("position").Find(&tenants).Error; err != nil {
+		return nil, nil, err
+	}
+	return &runs[0], tenants, nil
+}
+
+// UpdateRun updates the status and fingerprint of the migration run.
+func UpdateRun(db *gorm.DB, run *MigrationRun) error {
+	run.UpdatedAt = time.Now()
+	return publicTable(db, run).Model(&MigrationRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+		"fingerprint": run.Fingerprint,
+		"status":      run.Status,
+		"updated_at":  run.UpdatedAt,
+	}).Error
+}
+
+// UpdateRunTenant updates the status, fingerprint and error of a tenant within a migration run.
+func UpdateRunTenant(db *gorm.DB, tenant *MigrationRunTenant) error {
+	tenant.UpdatedAt = time.Now()
+	return publicTable(db, tenant).Model(&MigrationRunTenant{}).
+		Where("run_id = ? AND tenant_id = ?", tenant.RunID, tenant.TenantID).
+		Updates(map[string]interface{}{
+			"status":      tenant.Status,
+			"fingerprint": tenant.Fingerprint,
+			"error":       tenant.Error,
+			"updated_at":  tenant.UpdatedAt,
+		}).Error
+}
@@ -0,0 +1,71 @@
+package migrator
+
+import (
+	"time"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantSeeder records a seeder that has been run for a tenant. It is stored in the public schema
+// of the dialector. Not intended for direct use in application code.
+type TenantSeeder struct {
+	TenantID   string    `gorm:"column:tenant_id;primaryKey;size:63"`
+	SeederName string    `gorm:"column:seeder_name;primaryKey;size:255"`
+	SeededAt   time.Time `gorm:"column:seeded_at;not null"`
+}
+
+var _ driver.TenantTabler = new(TenantSeeder)
+
+// TableName implements [driver.TenantTabler].
+func (TenantSeeder) TableName() string {
+	return driver.PublicSchemaName() + ".gmt_tenant_seeders"
+}
+
+// IsSharedModel implements [driver.TenantTabler].
+func (TenantSeeder) IsSharedModel() bool { return true }
+
+// LoadSeeders returns the names of the seeders that have been run for the tenant. The
+// [TenantSeeder] table is created if it does not exist yet, so that [ClaimSeeder] can be called
+// within a transaction.
+func LoadSeeders(db *gorm.DB, tenantID string) (map[string]bool, error) {
+	if err := ensureTables(db, &TenantSeeder{}); err != nil {
+		return nil, err
+	}
+	var records []TenantSeeder
+	if err := publicTable(db, &TenantSeeder{}).Where("tenant_id = ?", tenantID).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	seeders := make(map[string]bool, len(records))
+	for _, record := range records {
+		seeders[record.SeederName] = true
+	}
+	return seeders, nil
+}
+
+// ClaimSeeder records the seeder as run for the tenant, and reports whether it was recorded by this
+// call. It reports false if the seeder has already been recorded for the tenant, such as by a
+// concurrent migration. The seeder should be run within the same transaction once claimed, so that
+// the record is rolled back if the seeder fails, and concurrent claims of the seeder wait for the
+// transaction to end.
+func ClaimSeeder(db *gorm.DB, tenantID, name string) (bool, error) {
+	record := &TenantSeeder{
+		TenantID:   tenantID,
+		SeederName: name,
+		SeededAt:   time.Now(),
+	}
+	result := publicTable(db, record).Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// deleteSeeders removes the seeders recorded for the tenant, if any.
+func deleteSeeders(db *gorm.DB, tenantID string) error {
+	if !publicTable(db, &TenantSeeder{}).Migrator().HasTable(&TenantSeeder{}) {
+		return nil
+	}
+	return publicTable(db, &TenantSeeder{}).Where("tenant_id = ?", tenantID).Delete(&TenantSeeder{}).Error
+}
@@ -0,0 +1,84 @@
+package migrator
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantMigration records the model fingerprint of the last successful migration of a tenant.
+// It is stored in the public schema of the dialector and maintained by the drivers. Not intended
+// for direct use in application code.
+type TenantMigration struct {
+	TenantID    string    `gorm:"column:tenant_id;primaryKey;size:63"`
+	Fingerprint string    `gorm:"column:fingerprint;size:64;not null"`
+	MigratedAt  time.Time `gorm:"column:migrated_at;not null"`
+}
+
+var _ driver.TenantTabler = new(TenantMigration)
+
+// TableName implements [driver.TenantTabler].
+func (TenantMigration) TableName() string {
+	return driver.PublicSchemaName() + ".gmt_tenant_migrations"
+}
+
+// IsSharedModel implements [driver.TenantTabler].
+func (TenantMigration) IsSharedModel() bool { return true }
+
+// LoadFingerprint returns the fingerprint stored for the tenant by the last successful migration,
+// or an empty string if the tenant has not been migrated yet.
+func LoadFingerprint(db *gorm.DB, tenantID string) (string, error) {
+	if !publicTable(db, &TenantMigration{}).Migrator().HasTable(&TenantMigration{}) {
+		return "", nil
+	}
+	var records []TenantMigration
+	if err := publicTable(db, &TenantMigration{}).Where("tenant_id = ?", tenantID).Limit(1).Find(&records).Error; err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "", nil
+	}
+	return records[0].Fingerprint, nil
+}
+
+// SaveFingerprint stores the fingerprint for the tenant, creating the [TenantMigration] table if
+// it does not exist yet.
+func SaveFingerprint(db *gorm.DB, tenantID, fingerprint string) error {
+	if err := ensureTables(db, &TenantMigration{}); err != nil {
+		return err
+	}
+	record := &TenantMigration{
+		TenantID:    tenantID,
+		Fingerprint: fingerprint,
+		MigratedAt:  time.Now(),
+	}
+	return publicTable(db, record).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"fingerprint", "migrated_at"}),
+	}).Create(record).Error
+}
+
+// ForgetTenant removes the migration records stored for the tenant, if any: its model fingerprint,
+// its model group subscriptions and the seeders run for it. Drivers call it once the tenant's
+// schema or database has been dropped.
+func ForgetTenant(db *gorm.DB, tenantID string) error {
+	if publicTable(db, &TenantMigration{}).Migrator().HasTable(&TenantMigration{}) {
+		if err := publicTable(db, &TenantMigration{}).Where("tenant_id = ?", tenantID).Delete(&TenantMigration{}).Error; err != nil {
+			return err
+		}
+	}
+	if err := deleteModelGroups(db, tenantID); err != nil {
+		return err
+	}
+	return deleteSeeders(db, tenantID)
+}
+
+// publicTable scopes the db to the table of the model in the public schema of the db (see
+// [driver.PublicSchemaOf]), as the table name of the model refers to the default public schema.
+func publicTable(db *gorm.DB, model driver.TenantTabler) *gorm.DB {
+	_, name, _ := strings.Cut(model.TableName(), ".")
+	return db.Table(driver.PublicSchemaOf(db) + "." + name)
+}
@@ -0,0 +1,31 @@
+package migrator
+
+import (
+	"testing"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type publicSchemaDialector struct {
+	tests.DummyDialector
+}
+
+func (publicSchemaDialector) PublicSchema() string { return "billing" }
+
+func TestPublicTable(t *testing.T) {
+	t.Setenv(driver.PublicSchemaEnvVar, "")
+
+	db, err := gorm.Open(publicSchemaDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, "`billing`.`gmt_tenant_migrations`", publicTable(db, &TenantMigration{}).Statement.TableExpr.SQL)
+	assert.Equal(t, "`billing`.`gmt_migration_runs`", publicTable(db, &MigrationRun{}).Statement.TableExpr.SQL)
+
+	db, err = gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, "`public`.`gmt_tenant_model_groups`", publicTable(db, &TenantModelGroup{}).Statement.TableExpr.SQL)
+	assert.Equal(t, "`public`.`gmt_tenant_seeders`", publicTable(db, &TenantSeeder{}).Statement.TableExpr.SQL)
+}
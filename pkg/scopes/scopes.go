@@ -4,6 +4,7 @@ Package scopes provides a set of predefined multitenancy scopes for GORM.
 package scopes
 
 import (
+	"cmp"
 	"reflect"
 
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
@@ -20,6 +21,8 @@ var tenantTablerType = reflect.TypeFor[driver.TenantTabler]()
 //  1. Direct specification via [gorm.DB.Table].
 //  2. Model type set via [gorm.DB.Model] implementing [driver.TenantTabler].
 //  3. Destination type implementing [driver.TenantTabler].
+//  4. Model or destination type declaring its tenancy with an embedded [driver.TenantTable],
+//     or a `gmt` struct tag, with the table name derived from GORM's naming strategy.
 //
 // If the table name cannot be determined, an error is added to the DB instance.
 //
@@ -54,9 +57,9 @@ func WithTenantSchema(tenant string) func(db *gorm.DB) *gorm.DB {
 		var tableName string
 		switch {
 		case db.Statement.Model != nil:
-			tableName = tableNameFromInterface(db.Statement.Model)
+			tableName = cmp.Or(tableNameFromInterface(db.Statement.Model), declaredTableName(db, db.Statement.Model))
 		case db.Statement.Dest != nil:
-			tableName = tableNameFromInterface(db.Statement.Dest)
+			tableName = cmp.Or(tableNameFromInterface(db.Statement.Dest), declaredTableName(db, db.Statement.Dest))
 		}
 		if tableName != "" {
 			return db.Table(tenant + "." + tableName)
@@ -66,6 +69,20 @@ func WithTenantSchema(tenant string) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
+// declaredTableName returns the table name GORM derives for a model that declares its tenancy
+// rather than implementing [driver.TenantTabler] (see [driver.DeclaredTenancy]), or an empty
+// string if the model does not declare its tenancy.
+func declaredTableName(db *gorm.DB, val interface{}) string {
+	if _, ok, err := driver.DeclaredTenancy(val); err != nil || !ok {
+		return ""
+	}
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(val); err != nil {
+		return ""
+	}
+	return stmt.Schema.Table
+}
+
 // tableNameFromInterface attempts to determine the table name from the provided interface value.
 // It supports values that directly implement the `driver.TenantTabler` interface or are struct types
 // that could potentially implement the interface. For slices or arrays, it attempts to infer the table name
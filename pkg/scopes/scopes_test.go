@@ -20,6 +20,12 @@ var _ driver.TenantTabler = new(Book)
 func (Book) TableName() string   { return "books" }
 func (Book) IsSharedModel() bool { return true }
 
+type Chapter struct {
+	driver.TenantTable
+	ID    uint
+	Title string
+}
+
 // assertEqualSQL for assert that the sql is equal, this method will ignore quote, and dialect specials.
 func assertEqualSQL(t *testing.T, db *gorm.DB, expected string, actually string) {
 	t.Helper()
@@ -144,14 +150,28 @@ func TestWithTenantSchema(t *testing.T) {
 			expected: `SELECT * FROM "tenant1"."books"`,
 		},
 		{
-			name: "10 - Invalid: Tabler interface not implemented",
+			name: "10 - With declared tenancy model set",
+			queryFn: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&Chapter{}).Scopes(WithTenantSchema("tenant1")).Find(&Chapter{})
+			},
+			expected: `SELECT * FROM "tenant1"."chapters"`,
+		},
+		{
+			name: "11 - With declared tenancy dest slice",
+			queryFn: func(tx *gorm.DB) *gorm.DB {
+				return tx.Scopes(WithTenantSchema("tenant1")).Find(&[]Chapter{})
+			},
+			expected: `SELECT * FROM "tenant1"."chapters"`,
+		},
+		{
+			name: "12 - Invalid: Tabler interface not implemented",
 			queryFn: func(tx *gorm.DB) *gorm.DB {
 				return tx.Scopes(WithTenantSchema("tenant3")).Find(&struct{}{})
 			},
 			expected: ``,
 		},
 		{
-			name: "11 - Invalid: Tabler interface not implemented (slice/array)",
+			name: "13 - Invalid: Tabler interface not implemented (slice/array)",
 			queryFn: func(tx *gorm.DB) *gorm.DB {
 				return tx.Scopes(WithTenantSchema("tenant3")).Find(&[]struct{}{})
 			},
@@ -0,0 +1,39 @@
+// Package tenant provides a framework-neutral way to carry the tenant of a request in a
+// [context.Context].
+//
+// The tenant middleware of the middleware packages stores the tenant in the standard request
+// context with [NewContext], in addition to their framework specific keys, so that shared service
+// layers can retrieve it with [FromContext], whatever router or server served the request:
+//
+//	func (s *BookService) List(ctx context.Context) ([]Book, error) {
+//	    t, ok := tenant.FromContext(ctx)
+//	    if !ok {
+//	        return nil, errors.New("no tenant in context")
+//	    }
+//	    ...
+//	}
+package tenant
+
+import "context"
+
+// Tenant identifies a tenant, such as by its schema (PostgreSQL) or database (MySQL) name.
+type Tenant string
+
+// String returns the tenant as a string.
+func (t Tenant) String() string {
+	return string(t)
+}
+
+// contextKey is the key holding the [Tenant] in a context.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the tenant.
+func NewContext(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant carried by ctx, and whether it carries a non-empty one.
+func FromContext(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(contextKey{}).(Tenant)
+	return t, ok && t != ""
+}
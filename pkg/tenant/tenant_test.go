@@ -0,0 +1,26 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	got, ok := FromContext(context.Background())
+	assert.False(t, ok, "FromContext() should not find a tenant")
+	assert.Empty(t, got)
+
+	ctx := NewContext(context.Background(), "tenant1")
+	got, ok = FromContext(ctx)
+	assert.True(t, ok, "FromContext() should find the tenant")
+	assert.Equal(t, Tenant("tenant1"), got)
+	assert.Equal(t, "tenant1", got.String())
+
+	_, ok = FromContext(NewContext(ctx, ""))
+	assert.False(t, ok, "FromContext() should not find an empty tenant")
+
+	_, ok = FromContext(context.WithValue(context.Background(), contextKey{}, "tenant1"))
+	assert.False(t, ok, "FromContext() should only find values of type Tenant")
+}
@@ -1,25 +1,48 @@
 package postgres
 
 import (
+	"cmp"
 	"fmt"
 	"time"
 
+	"github.com/bartventer/gorm-multitenancy/postgres/v8/schema"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/backoff"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/logext"
+	gmtmigrator "github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/migrator"
 )
 
+// DefaultExtensionSchema is the default schema into which the extensions declared with
+// [Options].Extensions are installed.
+const DefaultExtensionSchema = "extensions"
+
 type (
 	// Options provides configuration options with multitenancy support.
 	// By default, retry is enabled. To disable retry, set DisableRetry to true.
 	// Note that the retry logic is only applied to migrations.
+	//
+	// When OnlineMigration is true, tenant migrations avoid long-held locks on existing tables;
+	// see [Migrator.MigrateTenantModels]. LockTimeout bounds how long migration DDL waits for a
+	// lock, and defaults to 5 seconds in online mode.
+	//
+	// PublicSchema is the schema holding the tables of shared models, defaulting to
+	// [driver.PublicSchemaName].
+	//
+	// Extensions lists the PostgreSQL extensions required by the models, such as "citext" or
+	// "pg_trgm". They are installed into ExtensionSchema, defaulting to [DefaultExtensionSchema],
+	// by the shared migration, and the schema is appended to the search path of every tenant.
 	Options struct {
-		DisableRetry bool            `json:"gmt_disable_retry" mapstructure:"gmt_disable_retry"` // Whether to disable retry.
-		Retry        backoff.Options `json:",inline"           mapstructure:",squash"`           // Retry options.
+		DisableRetry    bool            `json:"gmt_disable_retry"    mapstructure:"gmt_disable_retry"`    // Whether to disable retry.
+		OnlineMigration bool            `json:"gmt_online_migration" mapstructure:"gmt_online_migration"` // Whether to migrate tenants in online mode.
+		LockTimeout     time.Duration   `json:"gmt_lock_timeout"     mapstructure:"gmt_lock_timeout"`     // Lock timeout for migration DDL.
+		PublicSchema    string          `json:"gmt_public_schema"    mapstructure:"gmt_public_schema"`    // Schema holding the shared tables.
+		Extensions      []string        `json:"gmt_extensions"       mapstructure:"gmt_extensions"`       // Extensions installed by the shared migration.
+		ExtensionSchema string          `json:"gmt_extension_schema" mapstructure:"gmt_extension_schema"` // Schema holding the extensions.
+		Retry           backoff.Options `json:",inline"              mapstructure:",squash"`              // Retry options.
 	}
 
 	// Option is a function that modifies an [Options] instance.
@@ -55,6 +78,14 @@ func (o *Options) apply(opts ...Option) {
 		o.Retry.Interval = max(o.Retry.Interval, time.Second*2)
 		o.Retry.MaxInterval = max(o.Retry.MaxInterval, time.Second*30)
 	}
+
+	if o.OnlineMigration && o.LockTimeout == 0 {
+		o.LockTimeout = time.Second * 5
+	}
+
+	if len(o.Extensions) > 0 && o.ExtensionSchema == "" {
+		o.ExtensionSchema = DefaultExtensionSchema
+	}
 }
 
 var _ gorm.Dialector = new(Dialector)
@@ -68,7 +99,7 @@ func Open(dsn string) gorm.Dialector {
 	options.apply()
 	return &Dialector{
 		Dialector: postgres.Open(dsn).(*postgres.Dialector),
-		registry:  &driver.ModelRegistry{},
+		registry:  newModelRegistry(options.PublicSchema),
 		logger:    logext.Default(),
 		options:   &options,
 	}
@@ -80,12 +111,22 @@ func New(config Config, opts ...Option) gorm.Dialector {
 	options.apply(opts...)
 	return &Dialector{
 		Dialector: postgres.New(config.Config).(*postgres.Dialector),
-		registry:  &driver.ModelRegistry{},
+		registry:  newModelRegistry(options.PublicSchema),
 		logger:    logext.Default(),
 		options:   options,
 	}
 }
 
+// Initialize implements [gorm.Dialector]. Along with initializing the embedded dialector, it
+// registers the callbacks directing the statements of the registered shared models that declare
+// their tenancy to the public schema (see [driver.RegisterTenancyCallbacks]).
+func (dialector Dialector) Initialize(db *gorm.DB) error {
+	if err := dialector.Dialector.Initialize(db); err != nil {
+		return err
+	}
+	return driver.RegisterTenancyCallbacks(db, dialector.registry)
+}
+
 // Migrator returns a [gorm.Migrator] implementation for the Dialector.
 func (dialector Dialector) Migrator(db *gorm.DB) gorm.Migrator {
 	return &Migrator{
@@ -103,19 +144,105 @@ func (dialector Dialector) Migrator(db *gorm.DB) gorm.Migrator {
 }
 
 // RegisterModels registers the given models with the dialector for multitenancy support.
+// Models are added to the models already registered; a model replaces any registered model
+// with the same table name. Safe for concurrent use by multiple goroutines.
 func (dialector *Dialector) RegisterModels(models ...driver.TenantTabler) error {
-	registry, err := driver.NewModelRegistry(models...)
-	if err != nil {
+	if err := dialector.registry.Register(models...); err != nil {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to register models: %w", err))
 	}
-	dialector.registry = registry
 	return nil
 }
 
+// UnregisterModels removes the given models from the models registered with the dialector.
+// Safe for concurrent use by multiple goroutines.
+func (dialector *Dialector) UnregisterModels(models ...driver.TenantTabler) {
+	dialector.registry.Unregister(models...)
+}
+
+// RegisterObjects registers the given SQL objects with the dialector, to be created alongside the
+// registered models (see [driver.SQLObject]). Safe for concurrent use by multiple goroutines.
+func (dialector *Dialector) RegisterObjects(objects ...driver.SQLObject) error {
+	if err := dialector.registry.RegisterObjects(objects...); err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to register SQL objects: %w", err))
+	}
+	return nil
+}
+
+// UnregisterObjects removes the SQL objects with the given names from the dialector.
+// Safe for concurrent use by multiple goroutines.
+func (dialector *Dialector) UnregisterObjects(names ...string) {
+	dialector.registry.UnregisterObjects(names...)
+}
+
+var _ driver.ModelRegistryProvider = new(Dialector)
+var _ driver.PublicSchemaProvider = new(Dialector)
+var _ schema.ExtensionSchemaProvider = new(Dialector)
+
+// newModelRegistry returns an empty model registry validating shared models against the public schema.
+func newModelRegistry(publicSchema string) *driver.ModelRegistry {
+	registry := &driver.ModelRegistry{}
+	registry.SetPublicSchema(publicSchema)
+	return registry
+}
+
+// PublicSchema implements [driver.PublicSchemaProvider].
+func (dialector Dialector) PublicSchema() string {
+	if dialector.options == nil {
+		return driver.PublicSchemaName()
+	}
+	return cmp.Or(dialector.options.PublicSchema, driver.PublicSchemaName())
+}
+
+// ExtensionSchema implements [schema.ExtensionSchemaProvider]. It returns an empty string if no
+// extensions are declared.
+func (dialector Dialector) ExtensionSchema() string {
+	if dialector.options == nil || len(dialector.options.Extensions) == 0 {
+		return ""
+	}
+	return dialector.options.ExtensionSchema
+}
+
+// ModelRegistry implements [driver.ModelRegistryProvider].
+func (dialector *Dialector) ModelRegistry() *driver.ModelRegistry {
+	return dialector.registry
+}
+
 // RegisterModels registers the given models with the provided [gorm.DB] instance for multitenancy support.
-// Not safe for concurrent use by multiple goroutines.
-func RegisterModels(db *gorm.DB, models ...driver.TenantTabler) error {
-	return db.Dialector.(*Dialector).RegisterModels(models...)
+// Models either implement [driver.TenantTabler], or declare their tenancy with an embedded
+// [driver.SharedTable] or [driver.TenantTable], or a `gmt` struct tag (see [driver.ResolveModels]).
+// The relationships of the models are validated along with those of the models already registered
+// (see [driver.ValidateRelationships]).
+// Models are added to the models already registered. Safe for concurrent use by multiple goroutines.
+func RegisterModels(db *gorm.DB, models ...interface{}) error {
+	resolved, err := driver.ResolveModels(db, models...)
+	if err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to register models: %w", err))
+	}
+	return registerModels(db, resolved...)
+}
+
+// registerModels registers the resolved models with the provided [gorm.DB] instance.
+func registerModels(db *gorm.DB, models ...driver.TenantTabler) error {
+	validate := func(models []driver.TenantTabler) error {
+		return driver.ValidateRelationships(db, models...)
+	}
+	if err := db.Dialector.(*Dialector).registry.RegisterValidated(validate, models...); err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to register models: %w", err))
+	}
+	return nil
+}
+
+// UnregisterModels removes the given models from the models registered with the provided [gorm.DB] instance.
+// Models that cannot be registered are ignored. Safe for concurrent use by multiple goroutines.
+func UnregisterModels(db *gorm.DB, models ...interface{}) {
+	db.Dialector.(*Dialector).UnregisterModels(driver.ResolveRegistrableModels(db, models...)...)
+}
+
+// RegisterObjects registers the given SQL objects with the provided [gorm.DB] instance, to be
+// created alongside the registered models (see [driver.SQLObject]). Objects are added to the
+// objects already registered. Safe for concurrent use by multiple goroutines.
+func RegisterObjects(db *gorm.DB, objects ...driver.SQLObject) error {
+	return db.Dialector.(*Dialector).RegisterObjects(objects...)
 }
 
 // MigratePublicSchema migrates the public schema in the database.
@@ -126,7 +253,12 @@ func MigratePublicSchema(db *gorm.DB) error {
 }
 
 // MigrateTenantModels creates a new schema for a specific tenant in the PostgreSQL database.
-func MigrateTenantModels(db *gorm.DB, schemaName string) error {
+// Tenants whose stored model fingerprint matches the registered models are skipped, unless
+// [gmtmigrator.WithForce] is provided.
+func MigrateTenantModels(db *gorm.DB, schemaName string, opts ...gmtmigrator.MigrateOption) error {
+	if len(opts) > 0 {
+		db = gmtmigrator.WithMigrateOptions(opts...)(db)
+	}
 	return db.Connection(func(tx *gorm.DB) error {
 		return tx.Migrator().(*Migrator).MigrateTenantModels(schemaName)
 	})
@@ -38,49 +38,130 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 }
 
 // MigrateTenantModels creates a schema for a specific tenant and migrates the private tables.
+// The registered tenant SQL objects are dropped before, and recreated after, the tables are
+// migrated, within the same transaction. The stored model fingerprint is checked again once the
+// advisory lock of the tenant is held, so that concurrent migrations of the same tenant migrate
+// it only once.
+//
+// When [Options].LockTimeout is set, the migration transaction gives up on DDL that waits longer
+// than the timeout for a lock, instead of queueing all other queries on the table behind it.
+//
+// When [Options].OnlineMigration is enabled, changes to existing tables are applied without
+// blocking writes for long: indexes are created after the migration transaction has been
+// committed, using CREATE INDEX CONCURRENTLY, and foreign key and check constraints are added as
+// NOT VALID and validated after the commit. Indexes left invalid by an interrupted build are
+// rebuilt by the next migration. Online mode is not available when the migration runs within an
+// existing transaction, in which case the tables are migrated as usual.
 func (m Migrator) MigrateTenantModels(tenantID string) error {
 	m.logger.Printf("⏳ migrating tables for tenant %s", tenantID)
 
-	tenantModels := m.registry.TenantModels
+	tenantModels := m.registry.RegisteredTenantModels()
 	if len(tenantModels) == 0 {
 		return gmterrors.NewWithScheme(DriverName, errors.New("no tenant tables to migrate"))
 	}
 
+	plan, err := migrator.TenantPlanFromDB(m.DB, m.registry, tenantID)
+	if err != nil {
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to plan migration for tenant %s: %w", tenantID, err))
+	}
+	if plan.UpToDate {
+		m.logger.Printf("⏭️ private tables up to date for tenant %s, skipping", tenantID)
+		return nil
+	}
+
 	sqlstr := safe.QuoteRawSQLForTenant(m.DB, "CREATE SCHEMA IF NOT EXISTS ", tenantID)
 	if err := m.DB.Exec(sqlstr).Error; err != nil {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create schema for tenant %s: %w", tenantID, err))
 	}
 
-	err := m.DB.Transaction(func(tx *gorm.DB) error {
+	var online *onlineMigration
+	if m.options.OnlineMigration {
+		if inTransaction(m.DB) {
+			m.logger.Printf("online migration is not supported within a transaction, migrating tenant %s as usual", tenantID)
+		} else {
+			online = newOnlineMigration()
+		}
+	}
+
+	upToDate := false
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
 		err := m.acquireXact(tx, tenantID)
 		if err != nil {
 			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to acquire advisory lock for tenant %s: %w", tenantID, err))
 		}
+		// Check the fingerprint again under the lock, as a concurrent migration may have migrated
+		// the tenant since.
+		applied, err := plan.Applied(tx)
+		if err != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to load model fingerprint for tenant %s: %w", tenantID, err))
+		}
+		if applied {
+			upToDate = true
+			return nil
+		}
+		if m.options.LockTimeout > 0 {
+			if err := tx.Exec(lockTimeoutSQL(m.options.LockTimeout, true)).Error; err != nil {
+				return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to set lock timeout for tenant %s: %w", tenantID, err))
+			}
+		}
 		reset, searchPathErr := schema.SetSearchPath(tx, tenantID)
 		if searchPathErr != nil {
 			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to set search path to tenant %s: %w", tenantID, searchPathErr))
 		}
 		defer reset()
 
-		if err := tx.
+		migrateTx := tx
+		if online != nil {
+			migrateTx = tx.Set(onlineMigrationKey, online)
+		}
+		if err := migrator.DropObjects(tx, plan.Objects); err != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to drop SQL objects for tenant %s: %w", tenantID, err))
+		}
+		if err := migrateTx.
 			Scopes(migrator.WithOption(migrator.MigratorOption)).
-			AutoMigrate(driver.ModelsToInterfaces(tenantModels)...); err != nil {
+			AutoMigrate(driver.ModelsToInterfaces(plan.Models)...); err != nil {
 			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to migrate private tables for tenant %s: %w", tenantID, err))
 		}
+		if err := migrator.CreateObjects(tx, plan.Objects); err != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create SQL objects for tenant %s: %w", tenantID, err))
+		}
+		if online != nil {
+			// The fingerprint is saved once the deferred changes have been applied.
+			return nil
+		}
+		if err := plan.Save(tx); err != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to save model fingerprint for tenant %s: %w", tenantID, err))
+		}
 		m.logger.Printf("✅ private tables migrated for tenant %s", tenantID)
 		return nil
 	})
 	if err != nil {
 		return err
 	}
+	if upToDate {
+		m.logger.Printf("⏭️ private tables migrated concurrently for tenant %s, skipping", tenantID)
+		return nil
+	}
+
+	if online != nil {
+		if err := m.finishOnlineMigration(tenantID, online); err != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to apply online changes for tenant %s: %w", tenantID, err))
+		}
+		if err := plan.Save(m.DB); err != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to save model fingerprint for tenant %s: %w", tenantID, err))
+		}
+		m.logger.Printf("✅ private tables migrated for tenant %s (online)", tenantID)
+	}
 	return nil
 }
 
-// MigrateSharedModels migrates the public tables in the database.
+// MigrateSharedModels migrates the public tables in the database, and recreates the registered
+// shared SQL objects in the public schema. The extensions declared with [Options].Extensions are
+// installed into the extension schema beforehand.
 func (m Migrator) MigrateSharedModels() error {
 	m.logger.Println("⏳ migrating public tables")
 
-	publicModels := m.registry.SharedModels
+	publicModels := m.registry.RegisteredSharedModels()
 	if len(publicModels) == 0 {
 		return gmterrors.NewWithScheme(DriverName, errors.New("no public tables to migrate"))
 	}
@@ -99,12 +180,36 @@ func (m Migrator) MigrateSharedModels() error {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to begin transaction: %w", err))
 	}
 
-	err := m.acquireXact(tx, driver.PublicSchemaName())
+	public := m.PublicSchema()
+	err := m.acquireXact(tx, public)
 	if err != nil {
 		tx.Rollback()
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to acquire advisory lock: %w", err))
 	}
 
+	if err := tx.Exec(safe.QuoteRawSQLForTenant(tx, "CREATE SCHEMA IF NOT EXISTS ", public)).Error; err != nil {
+		tx.Rollback()
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create public schema: %w", err))
+	}
+
+	if err := m.installExtensions(tx); err != nil {
+		tx.Rollback()
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to install extensions: %w", err))
+	}
+
+	// Resolve the unqualified table names of the shared models that declare their tenancy, and
+	// the unqualified names in the SQL of the objects, against the public schema, and the types
+	// and functions of the extensions against the extension schema.
+	if err := tx.Exec(schema.SearchPathSQL(tx, "SET LOCAL search_path TO ", public)).Error; err != nil {
+		tx.Rollback()
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to set search path to public schema: %w", err))
+	}
+	objects := m.registry.SharedObjects()
+	if err := migrator.DropObjects(tx, objects); err != nil {
+		tx.Rollback()
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to drop shared SQL objects: %w", err))
+	}
+
 	if err := tx.
 		Scopes(migrator.WithOption(migrator.MigratorOption)).
 		AutoMigrate(driver.ModelsToInterfaces(publicModels)...); err != nil {
@@ -112,9 +217,66 @@ func (m Migrator) MigrateSharedModels() error {
 		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to migrate public tables: %w", err))
 	}
 
+	if err := migrator.CreateObjects(tx, objects); err != nil {
+		tx.Rollback()
+		return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to create shared SQL objects: %w", err))
+	}
+
 	return tx.Commit().Error
 }
 
+// installExtensions creates the extension schema and installs the declared extensions into it,
+// if any. Extensions that are already installed in another schema, such as public, are moved
+// into the extension schema; an error is returned for extensions that cannot be relocated.
+func (m Migrator) installExtensions(tx *gorm.DB) error {
+	extSchema := m.ExtensionSchema()
+	if extSchema == "" {
+		return nil
+	}
+	if err := tx.Exec(safe.QuoteRawSQLForTenant(tx, "CREATE SCHEMA IF NOT EXISTS ", extSchema)).Error; err != nil {
+		return fmt.Errorf("failed to create extension schema: %w", err)
+	}
+	for _, extension := range m.options.Extensions {
+		sqlstr := safe.QuoteRawSQLForTenant(tx, "CREATE EXTENSION IF NOT EXISTS ", extension)
+		sqlstr = safe.QuoteRawSQLForTenant(tx, sqlstr+" SCHEMA ", extSchema)
+		if err := tx.Exec(sqlstr).Error; err != nil {
+			return fmt.Errorf("failed to install extension %q: %w", extension, err)
+		}
+		if err := relocateExtension(tx, extension, extSchema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relocateExtension moves the installed extension into the extension schema, if it was installed
+// into another schema before it was declared.
+func relocateExtension(tx *gorm.DB, extension, extSchema string) error {
+	var installed struct {
+		Schema      string
+		Relocatable bool
+	}
+	err := tx.Raw(`SELECT n.nspname AS schema, e.extrelocatable AS relocatable
+FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace
+WHERE e.extname = ?`, extension).Scan(&installed).Error
+	if err != nil {
+		return fmt.Errorf("failed to look up the schema of extension %q: %w", extension, err)
+	}
+	if installed.Schema == "" || installed.Schema == extSchema {
+		return nil
+	}
+	if !installed.Relocatable {
+		return fmt.Errorf("extension %q is installed in schema %q, and cannot be moved to the extension schema %q; reinstall it into %q",
+			extension, installed.Schema, extSchema, extSchema)
+	}
+	sqlstr := safe.QuoteRawSQLForTenant(tx, "ALTER EXTENSION ", extension)
+	sqlstr = safe.QuoteRawSQLForTenant(tx, sqlstr+" SET SCHEMA ", extSchema)
+	if err := tx.Exec(sqlstr).Error; err != nil {
+		return fmt.Errorf("failed to move extension %q from schema %q to %q: %w", extension, installed.Schema, extSchema, err)
+	}
+	return nil
+}
+
 // DropSchemaForTenant drops the schema for a specific tenant.
 func (m Migrator) DropSchemaForTenant(tenant string) error {
 	m.logger.Printf("⏳ dropping schema for tenant %s", tenant)
@@ -124,6 +286,9 @@ func (m Migrator) DropSchemaForTenant(tenant string) error {
 		if err := m.DB.Exec(sqlstr).Error; err != nil {
 			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to drop schema for tenant %s: %w", tenant, err))
 		}
+		if err := migrator.ForgetTenant(m.DB, tenant); err != nil {
+			return gmterrors.NewWithScheme(DriverName, fmt.Errorf("failed to delete migration records for tenant %s: %w", tenant, err))
+		}
 		m.logger.Printf("✅ schema dropped for tenant %s", tenant)
 		return nil
 	})
@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bartventer/gorm-multitenancy/postgres/v8/schema"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormschema "gorm.io/gorm/schema"
+)
+
+const onlineMigrationKey = "gorm-multitenancy/postgres/online_migration"
+
+type (
+	// onlineMigration collects the DDL that is deferred until after the migration transaction
+	// has been committed when migrating in online mode.
+	onlineMigration struct {
+		created     map[string]struct{} // Tables created by the migration.
+		indexes     []onlineIndex       // Indexes to create concurrently.
+		constraints []onlineConstraint  // Constraints to validate.
+	}
+
+	onlineIndex struct {
+		value interface{}
+		name  string
+	}
+
+	onlineConstraint struct {
+		table string
+		name  string
+	}
+)
+
+func newOnlineMigration() *onlineMigration {
+	return &onlineMigration{created: make(map[string]struct{})}
+}
+
+func onlineMigrationFromDB(db *gorm.DB) (*onlineMigration, bool) {
+	if v, ok := db.Get(onlineMigrationKey); ok {
+		online, ok := v.(*onlineMigration)
+		return online, ok && online != nil
+	}
+	return nil, false
+}
+
+func (o *onlineMigration) isCreated(table string) bool {
+	_, ok := o.created[table]
+	return ok
+}
+
+func (o *onlineMigration) addConstraint(table, name string) {
+	for _, c := range o.constraints {
+		if c.table == table && c.name == name {
+			return
+		}
+	}
+	o.constraints = append(o.constraints, onlineConstraint{table: table, name: name})
+}
+
+// inTransaction reports whether the database is bound to a transaction.
+func inTransaction(db *gorm.DB) bool {
+	committer, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok && committer != nil
+}
+
+// lockTimeoutSQL returns the statement that sets the lock timeout, scoped to the current
+// transaction if local is true.
+func lockTimeoutSQL(timeout time.Duration, local bool) string {
+	scope := "SESSION"
+	if local {
+		scope = "LOCAL"
+	}
+	return fmt.Sprintf("SET %s lock_timeout = '%dms'", scope, timeout.Milliseconds())
+}
+
+// CreateTable records the tables created during an online migration, so that their indexes and
+// constraints are created directly, before delegating to the underlying migrator.
+func (m Migrator) CreateTable(values ...interface{}) error {
+	if online, ok := onlineMigrationFromDB(m.DB); ok {
+		for _, value := range values {
+			if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+				online.created[stmt.Table] = struct{}{}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return m.Migrator.CreateTable(values...)
+}
+
+// CreateIndex defers the creation of indexes on existing tables during an online migration;
+// they are created concurrently once the migration transaction has been committed.
+func (m Migrator) CreateIndex(value interface{}, name string) error {
+	if online, ok := onlineMigrationFromDB(m.DB); ok {
+		deferred := false
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			if !online.isCreated(stmt.Table) {
+				online.indexes = append(online.indexes, onlineIndex{value: value, name: name})
+				deferred = true
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		if deferred {
+			return nil
+		}
+	}
+	return m.Migrator.CreateIndex(value, name)
+}
+
+// HasIndex reports whether the index exists. During an online migration, indexes left invalid
+// by an interrupted concurrent build are reported as missing, so that they are rebuilt.
+func (m Migrator) HasIndex(value interface{}, name string) bool {
+	if !m.Migrator.HasIndex(value, name) {
+		return false
+	}
+	if _, ok := onlineMigrationFromDB(m.DB); !ok {
+		return true
+	}
+	valid := true
+	_ = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			name = idx.Name
+		}
+		valid = m.isValidIndex(stmt, name)
+		return nil
+	})
+	return valid
+}
+
+// isValidIndex reports whether the named index in the current schema is valid, that is, not
+// left behind by an interrupted concurrent build. Missing indexes are reported as valid.
+func (m Migrator) isValidIndex(stmt *gorm.Statement, name string) bool {
+	valid := true
+	currentSchema, _ := m.CurrentSchema(stmt, stmt.Table)
+	_ = m.DB.Raw(
+		"SELECT i.indisvalid FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid "+
+			"JOIN pg_namespace n ON n.oid = c.relnamespace WHERE c.relname = ? AND n.nspname = ?",
+		name, currentSchema,
+	).Row().Scan(&valid)
+	return valid
+}
+
+// CreateConstraint adds foreign key and check constraints on existing tables as NOT VALID during
+// an online migration; they are validated once the migration transaction has been committed.
+func (m Migrator) CreateConstraint(value interface{}, name string) error {
+	online, ok := onlineMigrationFromDB(m.DB)
+	if !ok {
+		return m.Migrator.CreateConstraint(value, name)
+	}
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		constraint, table := m.GuessConstraintInterfaceAndTable(stmt, name)
+		switch constraint.(type) {
+		case *gormschema.Constraint, *gormschema.CheckConstraint:
+		default:
+			return m.Migrator.CreateConstraint(value, name)
+		}
+		if online.isCreated(table) {
+			return m.Migrator.CreateConstraint(value, name)
+		}
+		var tableExpr interface{} = clause.Table{Name: table}
+		if stmt.TableExpr != nil {
+			tableExpr = stmt.TableExpr
+		}
+		sql, values := constraint.Build()
+		if err := m.DB.Exec("ALTER TABLE ? ADD "+sql+" NOT VALID", append([]interface{}{tableExpr}, values...)...).Error; err != nil {
+			return err
+		}
+		online.addConstraint(table, constraint.GetName())
+		return nil
+	})
+}
+
+// HasConstraint reports whether the constraint exists. During an online migration, constraints
+// that have not been validated yet are scheduled for validation.
+func (m Migrator) HasConstraint(value interface{}, name string) bool {
+	if !m.Migrator.HasConstraint(value, name) {
+		return false
+	}
+	online, ok := onlineMigrationFromDB(m.DB)
+	if !ok {
+		return true
+	}
+	_ = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		constraint, table := m.GuessConstraintInterfaceAndTable(stmt, name)
+		if constraint != nil {
+			name = constraint.GetName()
+		}
+		currentSchema, curTable := m.CurrentSchema(stmt, table)
+		validated := true
+		if err := m.DB.Raw(
+			"SELECT con.convalidated FROM pg_constraint con JOIN pg_class c ON c.oid = con.conrelid "+
+				"JOIN pg_namespace n ON n.oid = c.relnamespace WHERE con.conname = ? AND c.relname = ? AND n.nspname = ?",
+			name, curTable, currentSchema,
+		).Row().Scan(&validated); err != nil {
+			return err
+		}
+		if !validated {
+			online.addConstraint(table, name)
+		}
+		return nil
+	})
+	return true
+}
+
+// finishOnlineMigration creates the deferred indexes concurrently and validates the constraints
+// that were added as NOT VALID. It must be called outside of a transaction, on the connection
+// used for the migration.
+func (m Migrator) finishOnlineMigration(tenantID string, online *onlineMigration) (err error) {
+	if len(online.indexes) == 0 && len(online.constraints) == 0 {
+		return nil
+	}
+	reset, err := schema.SetSearchPath(m.DB, tenantID)
+	if err != nil {
+		return err
+	}
+	defer reset()
+
+	if m.options.LockTimeout > 0 {
+		if err = m.DB.Exec(lockTimeoutSQL(m.options.LockTimeout, false)).Error; err != nil {
+			return fmt.Errorf("failed to set lock timeout: %w", err)
+		}
+		defer m.DB.Exec("RESET lock_timeout")
+	}
+
+	for _, idx := range online.indexes {
+		if err = m.createIndexConcurrently(idx.value, idx.name); err != nil {
+			return fmt.Errorf("failed to create index %q concurrently: %w", idx.name, err)
+		}
+	}
+	for _, c := range online.constraints {
+		if err = m.DB.Exec("ALTER TABLE ? VALIDATE CONSTRAINT ?", clause.Table{Name: c.table}, clause.Column{Name: c.name}).Error; err != nil {
+			return fmt.Errorf("failed to validate constraint %q: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+// createIndexConcurrently creates the index with CREATE INDEX CONCURRENTLY, first dropping any
+// invalid index of the same name left behind by an interrupted build.
+func (m Migrator) createIndexConcurrently(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		idx := stmt.Schema.LookIndex(name)
+		if idx == nil {
+			return fmt.Errorf("failed to create index with name %v", name)
+		}
+		if !m.isValidIndex(stmt, idx.Name) {
+			if err := m.DB.Exec("DROP INDEX CONCURRENTLY IF EXISTS ?", clause.Column{Name: idx.Name}).Error; err != nil {
+				return err
+			}
+		}
+
+		opts := m.BuildIndexOptions(idx.Fields, stmt)
+		values := []interface{}{clause.Column{Name: idx.Name}, m.CurrentTable(stmt), opts}
+
+		createIndexSQL := "CREATE "
+		if idx.Class != "" {
+			createIndexSQL += idx.Class + " "
+		}
+		createIndexSQL += "INDEX CONCURRENTLY IF NOT EXISTS ? ON ?"
+		if idx.Type != "" {
+			createIndexSQL += " USING " + idx.Type + "(?)"
+		} else {
+			createIndexSQL += " ?"
+		}
+		if idx.Option != "" && strings.TrimSpace(strings.ToUpper(idx.Option)) != "CONCURRENTLY" {
+			createIndexSQL += " " + idx.Option
+		}
+		if idx.Where != "" {
+			createIndexSQL += " WHERE " + idx.Where
+		}
+		return m.DB.Exec(createIndexSQL, values...).Error
+	})
+}
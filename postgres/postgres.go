@@ -47,13 +47,47 @@ in the DSN connection string of [Open]. The following options are available:
   - `gmt_retry_interval`: The initial interval between retry attempts. Default is 2 seconds.
   - `gmt_retry_max_interval`: The maximum interval between retry attempts. Default is 30 seconds.
 
+# Public Schema
+
+Shared tables live in the schema named by [driver.PublicSchemaName], "public" by default. To use a
+different schema per dialector, set [Options].PublicSchema when calling [New], or specify
+`gmt_public_schema` in the DSN connection string of [Open]. The table names of shared models must
+be qualified with the configured schema.
+
+# Extensions
+
+Tenant schemas do not see extensions installed in the public schema, as the public schema is not
+on a tenant's search path. Declare the extensions required by the models, such as citext or
+pg_trgm, with [Options].Extensions, or `gmt_extensions` (comma-separated) in the DSN connection
+string of [Open]. [MigratePublicSchema] installs them into a dedicated schema, named by
+[Options].ExtensionSchema (`gmt_extension_schema`) and defaulting to [DefaultExtensionSchema], and
+[schema.SetSearchPath] appends that schema to the search path of every tenant. Extensions that are
+already installed in another schema, such as public, are moved into the extension schema with
+ALTER EXTENSION ... SET SCHEMA; migration fails for extensions that cannot be relocated.
+
+# Online Tenant Migrations
+
+By default, tenant models are migrated within a single transaction, which holds ACCESS EXCLUSIVE
+locks on altered tables and builds new indexes with a blocking CREATE INDEX until it commits. For
+large tenant tables, enable online mode by setting [Options].OnlineMigration when calling [New],
+or by specifying `gmt_online_migration=true` in the DSN connection string of [Open]. In online
+mode, indexes on existing tables are created with CREATE INDEX CONCURRENTLY after the migration
+transaction commits, and foreign key and check constraints are added as NOT VALID and validated
+afterwards. The following options are available:
+
+  - `gmt_online_migration`: Whether to migrate tenants in online mode. Default is false.
+  - `gmt_lock_timeout`: The maximum time migration DDL waits for a lock, e.g. `5s`. Default is
+    5 seconds in online mode, and no timeout otherwise.
+
 # Shared Model Migrations
 
 To migrate shared models, use [MigratePublicSchema].
 
 # Tenant Model Migrations
 
-To migrate tenant-specific models, use [MigrateTenantModels].
+To migrate tenant-specific models, use [MigrateTenantModels]. Tenants whose stored model
+fingerprint matches the registered models are skipped; pass [migrator.WithForce] to migrate
+them regardless.
 
 # Tenant Offboarding
 
@@ -69,6 +103,7 @@ To retrieve the identifier for the current tenant context, use [schema.CurrentSe
 
 [PostgreSQL]: https://www.postgresql.org
 [PostgreSQL connection strings]: https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING-URIS
+[migrator.WithForce]: https://pkg.go.dev/github.com/bartventer/gorm-multitenancy/v8/pkg/migrator#WithForce
 */
 package postgres
 
@@ -126,7 +161,7 @@ func (p *postgresAdapter) OffboardTenant(_ context.Context, db *gorm.DB, tenantI
 
 // RegisterModels implements [driver.DBFactory].
 func (p *postgresAdapter) RegisterModels(_ context.Context, db *gorm.DB, models ...driver.TenantTabler) error {
-	return RegisterModels(db, models...)
+	return registerModels(db, models...)
 }
 
 // UseTenant implements [driver.DBFactory].
@@ -5,8 +5,12 @@ import (
 	"testing"
 
 	"github.com/bartventer/gorm-multitenancy/postgres/v8/internal/testutil"
+	"github.com/bartventer/gorm-multitenancy/postgres/v8/schema"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
 	"github.com/bartventer/gorm-multitenancy/v8/pkg/drivertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -40,8 +44,120 @@ func newHarness[TB testing.TB](ctx context.Context, t TB) (drivertest.Harness, e
 	}, nil
 }
 
+func newOnlineMigrationHarness[TB testing.TB](ctx context.Context, t TB) (drivertest.Harness, error) {
+	db := testutil.NewDBWithOptions(t, ctx, func(dsn string) gorm.Dialector {
+		return New(Config{Config: postgres.Config{DSN: dsn}}, func(o *Options) {
+			o.OnlineMigration = true
+		})
+	})
+	return &harness{
+		adapter: &postgresAdapter{},
+		db:      db,
+	}, nil
+}
+
 var _ drivertest.Harness = new(harness)
 
 func TestPostgresConformance(t *testing.T) {
 	drivertest.RunConformanceTests(t, newHarness)
 }
+
+func TestPostgresConformanceOnlineMigration(t *testing.T) {
+	drivertest.RunConformanceTests(t, newOnlineMigrationHarness)
+}
+
+type extensionsAccount struct {
+	ID    uint
+	Email string `gorm:"type:citext;uniqueIndex"`
+}
+
+func (extensionsAccount) TableName() string   { return "accounts" }
+func (extensionsAccount) IsSharedModel() bool { return false }
+
+type extensionsTenant struct {
+	ID string `gorm:"primaryKey;size:63"`
+}
+
+func (extensionsTenant) TableName() string   { return "public.tenants" }
+func (extensionsTenant) IsSharedModel() bool { return true }
+
+func TestExtensions(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDBWithOptions(t, ctx, func(dsn string) gorm.Dialector {
+		return New(Config{Config: postgres.Config{DSN: dsn}}, func(o *Options) {
+			o.Extensions = []string{"citext"}
+		})
+	})
+	require.NoError(t, RegisterModels(db, &extensionsTenant{}, &extensionsAccount{}))
+	require.NoError(t, MigratePublicSchema(db))
+	require.NoError(t, MigrateTenantModels(db, "tenant1"))
+
+	var extSchema string
+	err := db.Raw("SELECT n.nspname FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace WHERE e.extname = 'citext'").
+		Scan(&extSchema).Error
+	require.NoError(t, err)
+	assert.Equal(t, DefaultExtensionSchema, extSchema)
+
+	err = db.Connection(func(tx *gorm.DB) error {
+		reset, err := schema.SetSearchPath(tx, "tenant1")
+		if err != nil {
+			return err
+		}
+		defer reset()
+		assert.Equal(t, "tenant1", schema.CurrentSearchPath(tx))
+
+		if err := tx.Create(&extensionsAccount{Email: "User@Example.com"}).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&extensionsAccount{}).Where("email = ?", "user@example.com").Count(&count).Error; err != nil {
+			return err
+		}
+		assert.Equal(t, int64(1), count, "citext comparison should be case-insensitive")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestExtensions_preinstalled(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDBWithOptions(t, ctx, func(dsn string) gorm.Dialector {
+		return New(Config{Config: postgres.Config{DSN: dsn}}, func(o *Options) {
+			o.Extensions = []string{"citext"}
+		})
+	})
+	require.NoError(t, db.Exec("CREATE EXTENSION IF NOT EXISTS citext SCHEMA public").Error)
+	require.NoError(t, RegisterModels(db, &extensionsTenant{}, &extensionsAccount{}))
+	require.NoError(t, MigratePublicSchema(db))
+
+	var extSchema string
+	err := db.Raw("SELECT n.nspname FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace WHERE e.extname = 'citext'").
+		Scan(&extSchema).Error
+	require.NoError(t, err)
+	assert.Equal(t, DefaultExtensionSchema, extSchema, "expected the extension to be moved into the extension schema")
+
+	require.NoError(t, MigrateTenantModels(db, "tenant1"))
+	err = db.Connection(func(tx *gorm.DB) error {
+		reset, err := schema.SetSearchPath(tx, "tenant1")
+		if err != nil {
+			return err
+		}
+		defer reset()
+		return tx.Create(&extensionsAccount{Email: "User@Example.com"}).Error
+	})
+	require.NoError(t, err)
+}
+
+func TestCurrentSearchPath_publicSchema(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDBWithOptions(t, ctx, func(dsn string) gorm.Dialector {
+		return New(Config{Config: postgres.Config{DSN: dsn}}, func(o *Options) {
+			o.PublicSchema = "shared"
+		})
+	})
+	err := db.Connection(func(tx *gorm.DB) error {
+		assert.Equal(t, "shared", schema.CurrentSearchPath(tx), "expected the default search path to report the public schema of the dialector")
+		return nil
+	})
+	require.NoError(t, err)
+}
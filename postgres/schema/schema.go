@@ -6,13 +6,47 @@ package schema
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/bartventer/gorm-multitenancy/postgres/v8/internal/safe"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
 	"gorm.io/gorm"
 )
 
+// ExtensionSchemaProvider is implemented by dialectors that install extensions into a dedicated
+// schema, which is appended to the search path by [SetSearchPath].
+type ExtensionSchemaProvider interface {
+	// ExtensionSchema returns the schema holding the extensions, or an empty string if none.
+	ExtensionSchema() string
+}
+
+// ExtensionSchemaOf returns the extension schema of the dialector of the given database
+// connection, or an empty string if the dialector does not implement [ExtensionSchemaProvider].
+func ExtensionSchemaOf(tx *gorm.DB) string {
+	if tx == nil || tx.Config == nil {
+		return ""
+	}
+	if provider, ok := tx.Dialector.(ExtensionSchemaProvider); ok {
+		return provider.ExtensionSchema()
+	}
+	return ""
+}
+
+// SearchPathSQL returns the SQL statement, starting with the given command (e.g. "SET search_path
+// TO " or "SET LOCAL search_path TO "), that sets the search path to the specified schema name,
+// followed by the extension schema of the connection's dialector, if any (see [ExtensionSchemaOf]).
+func SearchPathSQL(tx *gorm.DB, command, schemaName string) string {
+	sqlstr := safe.QuoteRawSQLForTenant(tx, command, schemaName)
+	if extSchema := ExtensionSchemaOf(tx); extSchema != "" && extSchema != schemaName {
+		sqlstr = safe.QuoteRawSQLForTenant(tx, sqlstr+", ", extSchema)
+	}
+	return sqlstr
+}
+
 // SetSearchPath sets the search path for the given database connection to the specified schema name.
-// It returns a function that can be used to reset the search path to the default value.
+// It returns a function that can be used to reset the search path to the public schema of the
+// connection's dialector (see [driver.PublicSchemaOf]). If the dialector installs extensions into
+// a dedicated schema, that schema is appended to the search path (see [ExtensionSchemaOf]).
 // This function does not perform any validation on the schemaName parameter. It is the
 // responsibility of the caller to ensure that the schemaName has been sanitized to avoid SQL
 // injection vulnerabilities.
@@ -27,7 +61,7 @@ import (
 //	if err != nil {
 //		// handle the error
 //	}
-//	defer reset() // reset the search path to 'public'
+//	defer reset() // reset the search path to the public schema
 //	// ... do operations with the database with the search path set to 'domain1'
 func SetSearchPath(tx *gorm.DB, schemaName string) (reset func() error, err error) {
 	tx = tx.Session(&gorm.Session{})
@@ -36,23 +70,51 @@ func SetSearchPath(tx *gorm.DB, schemaName string) (reset func() error, err erro
 		_ = tx.AddError(err)
 		return nil, err
 	}
-	sqlstr := safe.QuoteRawSQLForTenant(tx, "SET search_path TO ", schemaName)
+	sqlstr := SearchPathSQL(tx, "SET search_path TO ", schemaName)
 	if execErr := tx.Exec(sqlstr).Error; execErr != nil {
 		err = fmt.Errorf("failed to set search path %q: %w", schemaName, execErr)
 		_ = tx.AddError(err)
 		return nil, err
 	}
-	reset = func() error { return tx.Exec("SET search_path TO public").Error }
+	resetSQL := SearchPathSQL(tx, "SET search_path TO ", driver.PublicSchemaOf(tx))
+	reset = func() error { return tx.Exec(resetSQL).Error }
 	return reset, nil
 }
 
-// CurrentSearchPath returns the current search path for the given database connection.
+// CurrentSearchPath returns the current search path for the given database connection. The
+// extension schema appended by [SetSearchPath] is omitted, and PostgreSQL's default search path
+// is reported as the public schema of the dialector (see [driver.PublicSchemaOf]).
 func CurrentSearchPath(tx *gorm.DB) string {
 	tx = tx.Session(&gorm.Session{})
 	var searchPath string
 	_ = tx.Raw("SHOW search_path").Scan(&searchPath)
-	if searchPath == `"$user", public` {
-		return "public"
+	searchPath = trimExtensionSchema(searchPath, ExtensionSchemaOf(tx))
+	if searchPath == defaultSearchPath {
+		return driver.PublicSchemaOf(tx)
 	}
 	return searchPath
 }
+
+// defaultSearchPath is PostgreSQL's default search path, as reported by SHOW search_path.
+const defaultSearchPath = `"$user", public`
+
+// trimExtensionSchema removes the trailing extension schema from the search path, as reported by
+// SHOW search_path, unless it is the only schema.
+func trimExtensionSchema(searchPath, extSchema string) string {
+	if extSchema == "" {
+		return searchPath
+	}
+	head, last, found := cutLast(searchPath, ", ")
+	if !found || strings.Trim(last, `"`) != extSchema {
+		return searchPath
+	}
+	return head
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
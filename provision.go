@@ -0,0 +1,112 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/namespace"
+)
+
+// TenantIdentifier is implemented by shared tenant models that identify the schema (database, for
+// MySQL) of the tenant. [TenantModel] and [TenantPKModel] implement it.
+type TenantIdentifier interface {
+	// TenantSchemaName returns the schema name of the tenant.
+	TenantSchemaName() string
+}
+
+// TenantSchemaName implements [TenantIdentifier].
+func (m TenantModel) TenantSchemaName() string { return m.SchemaName }
+
+// TenantSchemaName implements [TenantIdentifier].
+func (m TenantPKModel) TenantSchemaName() string { return m.ID }
+
+// ProvisionTenant onboards a new tenant. It validates the tenant's schema name, inserts the tenant
+// row into the shared tenants table, and creates and migrates the tenant's schema with
+// [DB.MigrateTenantModels], running the registered seeders. The tenant is in the
+// [migrator.TenantProvisioning] state until it has been provisioned, and then becomes
+// [migrator.TenantActive].
+//
+// If any step fails, the steps already completed by this call are compensated: the schema is
+// dropped with [DB.OffboardTenant] if this call created it, the tenant row is deleted (permanently,
+// if the model is soft-deletable), and the previous lifecycle state of the tenant is restored, so
+// that a failed signup can be retried with the same schema name. The compensation is not affected
+// by the cancellation of ctx. Errors encountered while compensating are joined with the original
+// error.
+//
+// The tenant must be a pointer to a registered shared model implementing [TenantIdentifier], such
+// as a model embedding [TenantModel].
+//
+// Safe for concurrent use by multiple goroutines.
+func (db *DB) ProvisionTenant(ctx context.Context, tenant TenantIdentifier) (err error) {
+	tenantID := tenant.TenantSchemaName()
+	if err := namespace.Validate(tenantID); err != nil {
+		return err
+	}
+
+	var from migrator.TenantState
+	transitioned := false
+	if db.tracksLifecycle() {
+		from, err = migrator.TransitionTenantState(db.DB.WithContext(ctx), tenantID, migrator.TenantProvisioning)
+		if err != nil {
+			return gmterrors.New(fmt.Errorf("failed to transition tenant %s to %q: %w", tenantID, migrator.TenantProvisioning, err))
+		}
+		// A tenant that is already provisioning is being provisioned by a concurrent call, whose
+		// state must be left as is.
+		transitioned = from != migrator.TenantProvisioning
+	}
+
+	created, schemaCreated := false, false
+	defer func() {
+		if err == nil {
+			return
+		}
+		cleanupCtx := context.WithoutCancel(ctx)
+		if schemaCreated {
+			if offboardErr := db.OffboardTenant(cleanupCtx, tenantID); offboardErr != nil {
+				err = errors.Join(err, gmterrors.New(fmt.Errorf("failed to drop schema of tenant %s: %w", tenantID, offboardErr)))
+			}
+		}
+		if created {
+			if deleteErr := db.WithContext(cleanupCtx).Unscoped().Delete(tenant).Error; deleteErr != nil {
+				err = errors.Join(err, gmterrors.New(fmt.Errorf("failed to delete tenant %s: %w", tenantID, deleteErr)))
+			}
+		}
+		if transitioned {
+			if stateErr := migrator.RevertTenantState(db.DB.WithContext(cleanupCtx), tenantID, migrator.TenantProvisioning, from); stateErr != nil {
+				err = errors.Join(err, gmterrors.New(fmt.Errorf("failed to restore state of tenant %s: %w", tenantID, stateErr)))
+			}
+		}
+	}()
+
+	if err := db.WithContext(ctx).Create(tenant).Error; err != nil {
+		return gmterrors.New(fmt.Errorf("failed to create tenant %s: %w", tenantID, err))
+	}
+	created = true
+
+	exists, err := db.schemaExists(ctx, tenantID)
+	if err != nil {
+		return gmterrors.New(fmt.Errorf("failed to look up schema of tenant %s: %w", tenantID, err))
+	}
+	schemaCreated = !exists
+
+	if err := db.MigrateTenantModels(ctx, tenantID); err != nil {
+		return gmterrors.New(fmt.Errorf("failed to migrate tenant %s: %w", tenantID, err))
+	}
+	if db.tracksLifecycle() {
+		return db.TransitionTenant(ctx, tenantID, migrator.TenantActive)
+	}
+	return nil
+}
+
+// schemaExists reports whether the schema (database, for MySQL) of the tenant exists.
+func (db *DB) schemaExists(ctx context.Context, tenantID string) (bool, error) {
+	var count int64
+	err := db.DB.WithContext(ctx).
+		Table("information_schema.schemata").
+		Where("schema_name = ?", tenantID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,76 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type provisionTenant struct {
+	ID uint
+	TenantModel
+}
+
+type provisionDriver struct {
+	mockDriver
+	migrateErr error
+	migrated   []string
+	offboarded []string
+}
+
+func (d *provisionDriver) MigrateTenantModels(ctx context.Context, db *gorm.DB, tenantID string) error {
+	d.migrated = append(d.migrated, tenantID)
+	return d.migrateErr
+}
+
+func (d *provisionDriver) OffboardTenant(ctx context.Context, db *gorm.DB, tenantID string) error {
+	d.offboarded = append(d.offboarded, tenantID)
+	return nil
+}
+
+func TestDB_ProvisionTenant(t *testing.T) {
+	newDB := func(t *testing.T, d *provisionDriver) *DB {
+		t.Helper()
+		gdb, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+		require.NoError(t, err)
+		return NewDB(d, gdb)
+	}
+	newTenant := func(schemaName string) *provisionTenant {
+		return &provisionTenant{TenantModel: TenantModel{DomainURL: schemaName + ".example.com", SchemaName: schemaName}}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		d := &provisionDriver{}
+		err := newDB(t, d).ProvisionTenant(context.Background(), newTenant("tenant1"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tenant1"}, d.migrated)
+		assert.Empty(t, d.offboarded)
+	})
+
+	t.Run("invalid schema name", func(t *testing.T) {
+		d := &provisionDriver{}
+		err := newDB(t, d).ProvisionTenant(context.Background(), newTenant("pg_tenant"))
+		require.Error(t, err)
+		assert.Empty(t, d.migrated, "tenant should not be migrated")
+	})
+
+	t.Run("migration failure", func(t *testing.T) {
+		wantErr := errors.New("migration failed")
+		d := &provisionDriver{migrateErr: wantErr}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := newDB(t, d).ProvisionTenant(ctx, newTenant("tenant1"))
+		require.ErrorIs(t, err, wantErr)
+		assert.Equal(t, []string{"tenant1"}, d.offboarded, "half-created schema should be dropped")
+	})
+}
+
+func TestTenantIdentifier(t *testing.T) {
+	assert.Equal(t, "tenant1", TenantModel{SchemaName: "tenant1"}.TenantSchemaName())
+	assert.Equal(t, "tenant2", TenantPKModel{ID: "tenant2"}.TenantSchemaName())
+}
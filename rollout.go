@@ -0,0 +1,184 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
+)
+
+var (
+	// ErrCanaryFailed is returned by [DB.RolloutTenantModels] when a canary tenant fails to
+	// migrate or verify.
+	ErrCanaryFailed = errors.New("canary tenants failed migration or verification")
+
+	// ErrRolloutHalted is returned by [DB.RolloutTenantModels] when the error rate of a wave
+	// exceeds [RolloutOptions].MaxErrorRate.
+	ErrRolloutHalted = errors.New("rollout halted, wave error rate exceeded threshold")
+)
+
+type (
+	// VerifyFunc verifies a tenant after its models have been migrated, for example by running
+	// queries or checking row counts with [DB.WithTenant]. A non-nil error marks the tenant as failed.
+	VerifyFunc func(ctx context.Context, db *DB, tenantID string) error
+
+	// RolloutOptions holds the options for a staged rollout of the tenant models.
+	RolloutOptions struct {
+		// Canary contains the tenants that are migrated and verified first. The rollout only
+		// continues to the remaining tenants if all canary tenants succeed. Defaults to the
+		// first 1% of the tenants, with a minimum of one tenant.
+		Canary []string
+
+		// WaveSize is the number of tenants migrated per wave after the canary stage.
+		// Defaults to 10% of the remaining tenants, with a minimum of one tenant.
+		WaveSize int
+
+		// MaxErrorRate is the fraction of tenants within a wave, between 0 and 1, that may fail
+		// before the rollout is halted. Defaults to 0, halting on the first failing wave.
+		MaxErrorRate float64
+
+		// Verify contains the functions used to verify each tenant after migration.
+		Verify []VerifyFunc
+
+		// MigrateOptions are passed to [DB.MigrateTenantModels] for each tenant.
+		MigrateOptions []migrator.MigrateOption
+	}
+
+	// RolloutOption is a function that modifies a [RolloutOptions] instance.
+	RolloutOption func(*RolloutOptions)
+
+	// RolloutResult reports the outcome of a staged rollout.
+	RolloutResult struct {
+		Completed []string         // Tenants that were migrated and verified.
+		Failed    map[string]error // Tenants that failed migration or verification.
+		Pending   []string         // Tenants that were not attempted because the rollout stopped.
+		Waves     int              // Number of waves completed after the canary stage.
+	}
+)
+
+// WithCanary sets the canary tenants of the rollout.
+func WithCanary(tenantIDs ...string) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.Canary = tenantIDs
+	}
+}
+
+// WithWaveSize sets the number of tenants migrated per wave.
+func WithWaveSize(size int) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.WaveSize = size
+	}
+}
+
+// WithMaxErrorRate sets the fraction of tenants within a wave that may fail before the rollout
+// is halted.
+func WithMaxErrorRate(rate float64) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.MaxErrorRate = rate
+	}
+}
+
+// WithVerify adds functions used to verify each tenant after migration.
+func WithVerify(fns ...VerifyFunc) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.Verify = append(o.Verify, fns...)
+	}
+}
+
+// WithRolloutMigrateOptions sets the options passed to [DB.MigrateTenantModels] for each tenant.
+func WithRolloutMigrateOptions(opts ...migrator.MigrateOption) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.MigrateOptions = append(o.MigrateOptions, opts...)
+	}
+}
+
+// RolloutTenantModels migrates the registered tenant-specific models for the given tenants in
+// stages. The canary tenants are migrated and verified first; if any of them fails, the rollout
+// stops and [ErrCanaryFailed] is returned. The remaining tenants are then migrated in waves,
+// and the rollout stops with [ErrRolloutHalted] as soon as the error rate of a wave exceeds
+// [RolloutOptions].MaxErrorRate.
+//
+// The result is returned even if the rollout stops, reporting the completed, failed and pending
+// tenants.
+func (db *DB) RolloutTenantModels(ctx context.Context, tenantIDs []string, opts ...RolloutOption) (*RolloutResult, error) {
+	options := RolloutOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.MaxErrorRate < 0 || options.MaxErrorRate > 1 {
+		return nil, gmterrors.New(fmt.Errorf("invalid max error rate %v, must be between 0 and 1", options.MaxErrorRate))
+	}
+
+	canary := options.Canary
+	if len(canary) == 0 && len(tenantIDs) > 0 {
+		canary = tenantIDs[:max(1, int(math.Ceil(float64(len(tenantIDs))*0.01)))]
+	}
+	inCanary := make(map[string]struct{}, len(canary))
+	for _, tenantID := range canary {
+		inCanary[tenantID] = struct{}{}
+	}
+	remaining := make([]string, 0, len(tenantIDs))
+	for _, tenantID := range tenantIDs {
+		if _, ok := inCanary[tenantID]; !ok {
+			remaining = append(remaining, tenantID)
+		}
+	}
+	waveSize := options.WaveSize
+	if waveSize <= 0 {
+		waveSize = max(1, int(math.Ceil(float64(len(remaining))*0.1)))
+	}
+
+	result := &RolloutResult{Failed: make(map[string]error)}
+	if errs := db.rolloutWave(ctx, canary, &options, result); len(errs) > 0 {
+		result.Pending = remaining
+		return result, gmterrors.New(errors.Join(append([]error{ErrCanaryFailed}, errs...)...))
+	}
+
+	for start := 0; start < len(remaining); start += waveSize {
+		wave := remaining[start:min(start+waveSize, len(remaining))]
+		errs := db.rolloutWave(ctx, wave, &options, result)
+		result.Waves++
+		if rate := float64(len(errs)) / float64(len(wave)); rate > options.MaxErrorRate {
+			result.Pending = remaining[start+len(wave):]
+			return result, gmterrors.New(errors.Join(append([]error{
+				fmt.Errorf("%w: wave %d error rate %.2f exceeds %.2f", ErrRolloutHalted, result.Waves, rate, options.MaxErrorRate),
+			}, errs...)...))
+		}
+	}
+	return result, nil
+}
+
+// rolloutWave migrates and verifies the given tenants, recording the outcome in the result.
+// It returns the errors of the tenants that failed.
+func (db *DB) rolloutWave(ctx context.Context, tenantIDs []string, options *RolloutOptions, result *RolloutResult) []error {
+	var errs []error
+	for _, tenantID := range tenantIDs {
+		if err := db.rolloutTenant(ctx, tenantID, options); err != nil {
+			err = fmt.Errorf("tenant %q: %w", tenantID, err)
+			result.Failed[tenantID] = err
+			errs = append(errs, err)
+			continue
+		}
+		result.Completed = append(result.Completed, tenantID)
+	}
+	return errs
+}
+
+// rolloutTenant migrates and verifies a single tenant.
+func (db *DB) rolloutTenant(ctx context.Context, tenantID string, options *RolloutOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := db.MigrateTenantModels(ctx, tenantID, options.MigrateOptions...); err != nil {
+		return err
+	}
+	for _, verify := range options.Verify {
+		if err := verify(ctx, db, tenantID); err != nil {
+			return fmt.Errorf("verification failed: %w", err)
+		}
+	}
+	return nil
+}
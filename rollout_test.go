@@ -0,0 +1,110 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type rolloutDriver struct {
+	mockDriver
+	fail     map[string]bool
+	migrated []string
+}
+
+func (m *rolloutDriver) MigrateTenantModels(ctx context.Context, db *gorm.DB, tenantID string) error {
+	if m.fail[tenantID] {
+		return errors.New("migration failed")
+	}
+	m.migrated = append(m.migrated, tenantID)
+	return nil
+}
+
+func makeTenantIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("tenant%d", i)
+	}
+	return ids
+}
+
+func TestDB_RolloutTenantModels(t *testing.T) {
+	t.Run("all waves", func(t *testing.T) {
+		d := &rolloutDriver{}
+		db := NewDB(d, &gorm.DB{})
+		tenantIDs := makeTenantIDs(10)
+		result, err := db.RolloutTenantModels(context.Background(), tenantIDs,
+			WithCanary("tenant9"),
+			WithWaveSize(4),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, append([]string{"tenant9"}, tenantIDs[:9]...), d.migrated, "expected canary to be migrated first")
+		assert.Len(t, result.Completed, 10)
+		assert.Empty(t, result.Failed)
+		assert.Empty(t, result.Pending)
+		assert.Equal(t, 3, result.Waves)
+	})
+
+	t.Run("default canary", func(t *testing.T) {
+		d := &rolloutDriver{fail: map[string]bool{"tenant0": true}}
+		db := NewDB(d, &gorm.DB{})
+		result, err := db.RolloutTenantModels(context.Background(), makeTenantIDs(150))
+		require.ErrorIs(t, err, ErrCanaryFailed)
+		assert.Equal(t, []string{"tenant1"}, d.migrated, "expected the first 1% of tenants as canary")
+		assert.Contains(t, result.Failed, "tenant0")
+		assert.Len(t, result.Pending, 148)
+	})
+
+	t.Run("verification failed", func(t *testing.T) {
+		d := &rolloutDriver{}
+		db := NewDB(d, &gorm.DB{})
+		verify := func(ctx context.Context, db *DB, tenantID string) error {
+			if tenantID == "tenant0" {
+				return errors.New("row count mismatch")
+			}
+			return nil
+		}
+		result, err := db.RolloutTenantModels(context.Background(), makeTenantIDs(5), WithVerify(verify))
+		require.ErrorIs(t, err, ErrCanaryFailed)
+		assert.ErrorContains(t, result.Failed["tenant0"], "row count mismatch")
+		assert.Empty(t, result.Completed)
+	})
+
+	t.Run("halted on error rate", func(t *testing.T) {
+		// Canary: tenant0, waves: [tenant1-3], [tenant4-6], [tenant7-9].
+		d := &rolloutDriver{fail: map[string]bool{"tenant4": true, "tenant5": true}}
+		db := NewDB(d, &gorm.DB{})
+		result, err := db.RolloutTenantModels(context.Background(), makeTenantIDs(10),
+			WithWaveSize(3),
+			WithMaxErrorRate(0.5),
+		)
+		require.ErrorIs(t, err, ErrRolloutHalted)
+		assert.Equal(t, 2, result.Waves)
+		assert.Len(t, result.Failed, 2)
+		assert.Equal(t, []string{"tenant0", "tenant1", "tenant2", "tenant3", "tenant6"}, result.Completed)
+		assert.Equal(t, makeTenantIDs(10)[7:], result.Pending)
+	})
+
+	t.Run("within error rate", func(t *testing.T) {
+		d := &rolloutDriver{fail: map[string]bool{"tenant3": true}}
+		db := NewDB(d, &gorm.DB{})
+		result, err := db.RolloutTenantModels(context.Background(), makeTenantIDs(10),
+			WithWaveSize(3),
+			WithMaxErrorRate(0.5),
+		)
+		require.NoError(t, err)
+		assert.Len(t, result.Failed, 1)
+		assert.Len(t, result.Completed, 9)
+	})
+
+	t.Run("invalid error rate", func(t *testing.T) {
+		db := NewDB(&rolloutDriver{}, &gorm.DB{})
+		_, err := db.RolloutTenantModels(context.Background(), makeTenantIDs(1), WithMaxErrorRate(2))
+		assert.Error(t, err)
+	})
+}
@@ -19,9 +19,12 @@ gotestflags=(
     ["."]="${gotestflagsbase[@]} -coverpkg=./..."
     ["./postgres"]="${gotestflagsbase[@]} -coverpkg=./..."
     ["./mysql"]="${gotestflagsbase[@]} -coverpkg=./..."
+    ["./middleware/chi"]="${gotestflagsbase[@]}"
     ["./middleware/echo"]="${gotestflagsbase[@]}"
+    ["./middleware/fiber"]="${gotestflagsbase[@]}"
     ["./middleware/gin"]="${gotestflagsbase[@]}"
     ["./middleware/iris"]="${gotestflagsbase[@]}"
+    ["./middleware/grpc"]="${gotestflagsbase[@]}"
     ["./middleware/nethttp"]="${gotestflagsbase[@]}"
     ["./_examples"]="${gotestflagsbase[@]} -coverpkg=./..."
 )
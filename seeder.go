@@ -0,0 +1,110 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/driver"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/gmterrors"
+	"github.com/bartventer/gorm-multitenancy/v8/pkg/migrator"
+)
+
+// Seeder seeds the tables of a tenant with initial data, such as default roles, settings and
+// lookup rows. Seeders are registered with [DB.RegisterSeeders] and run once per tenant by
+// [DB.MigrateTenantModels].
+type Seeder interface {
+	// Name returns the unique name of the seeder, under which it is recorded once it has been
+	// run for a tenant.
+	Name() string
+
+	// Seed seeds the tables of the tenant. The provided transaction is scoped to the tenant.
+	Seed(ctx context.Context, tx *DB) error
+}
+
+// seederFunc is a [Seeder] backed by a function.
+type seederFunc struct {
+	name string
+	seed func(ctx context.Context, tx *DB) error
+}
+
+func (s seederFunc) Name() string                           { return s.name }
+func (s seederFunc) Seed(ctx context.Context, tx *DB) error { return s.seed(ctx, tx) }
+
+// NewSeeder returns a [Seeder] with the given name that seeds a tenant by calling seed.
+func NewSeeder(name string, seed func(ctx context.Context, tx *DB) error) Seeder {
+	return seederFunc{name: name, seed: seed}
+}
+
+// RegisterSeeders registers seeders to be run for each tenant after its tables have been migrated
+// by [DB.MigrateTenantModels]. Seeders run in registration order; a seeder replaces any registered
+// seeder with the same name, keeping its position.
+//
+// Each seeder runs in its own transaction within the tenant context, and is recorded for the
+// tenant in the public schema when it succeeds, so that it runs exactly once per tenant, even if
+// the tenant is migrated concurrently. Seeders
+// registered later run on the next migration of each tenant, even if its tables are up to date.
+//
+// Safe for concurrent use by multiple goroutines.
+func (db *DB) RegisterSeeders(ctx context.Context, seeders ...Seeder) error {
+	registry := db.modelRegistry()
+	if registry == nil {
+		return gmterrors.New(errors.New("registering seeders is not supported by the dialector"))
+	}
+	named := make([]driver.NamedSeeder, 0, len(seeders))
+	for _, seeder := range seeders {
+		named = append(named, seeder)
+	}
+	return registry.RegisterSeeders(named...)
+}
+
+// runSeeders runs the registered seeders that have not been run for the tenant yet.
+func (db *DB) runSeeders(ctx context.Context, tenantID string) error {
+	registry := db.modelRegistry()
+	if registry == nil {
+		return nil
+	}
+	seeders := registry.Seeders()
+	if len(seeders) == 0 {
+		return nil
+	}
+	seeded, err := migrator.LoadSeeders(db.DB.WithContext(ctx), tenantID)
+	if err != nil {
+		return gmterrors.New(fmt.Errorf("failed to load seeders for tenant %s: %w", tenantID, err))
+	}
+	for _, named := range seeders {
+		seeder, ok := named.(Seeder)
+		if !ok {
+			return gmterrors.New(fmt.Errorf("seeder %q of type %T does not implement multitenancy.Seeder", named.Name(), named))
+		}
+		if seeded[seeder.Name()] {
+			continue
+		}
+		err := db.WithTenant(ctx, tenantID, func(tx *DB) error {
+			// The seeder is claimed before it runs, so that a concurrent migration of the tenant
+			// waits for this transaction, and skips the seeder once it has been committed.
+			claimed, err := migrator.ClaimSeeder(tx.DB, tenantID, seeder.Name())
+			if err != nil || !claimed {
+				return err
+			}
+			return seeder.Seed(ctx, tx)
+		})
+		if err != nil {
+			return gmterrors.New(fmt.Errorf("failed to run seeder %q for tenant %s: %w", seeder.Name(), tenantID, err))
+		}
+	}
+	return nil
+}
+
+// modelRegistry returns the model registry of the dialector, or nil if the dialector does not
+// expose its registered models.
+func (db *DB) modelRegistry() *driver.ModelRegistry {
+	if db.DB == nil || db.Config == nil {
+		return nil
+	}
+	provider, ok := db.Dialector.(driver.ModelRegistryProvider)
+	if !ok {
+		return nil
+	}
+	return provider.ModelRegistry()
+}
@@ -0,0 +1,28 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSeeder(t *testing.T) {
+	wantErr := errors.New("seed error")
+	var called bool
+	seeder := NewSeeder("roles", func(ctx context.Context, tx *DB) error {
+		called = true
+		return wantErr
+	})
+	assert.Equal(t, "roles", seeder.Name())
+	assert.ErrorIs(t, seeder.Seed(context.Background(), nil), wantErr)
+	assert.True(t, called)
+}
+
+func TestDB_RegisterSeeders(t *testing.T) {
+	db := NewDB(&mockDriver{}, &gorm.DB{})
+	err := db.RegisterSeeders(context.Background(), NewSeeder("roles", func(context.Context, *DB) error { return nil }))
+	assert.Error(t, err, "expected error for dialector without model registry")
+}